@@ -1,23 +1,27 @@
 /*
 Frontend hosts the API that consumers of the alicenet indexer use.
 
-It uses a shared Spanner database populated by the indexer worker process.
+It uses a shared Spanner, Postgres or SQLite database populated by the indexer worker process.
 */
 package main
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"flag"
 	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"cloud.google.com/go/spanner"
+	"contrib.go.opencensus.io/exporter/stackdriver"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	_ "github.com/lib/pq"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 
@@ -26,20 +30,118 @@ import (
 	"github.com/alicenet/utilities/internal/flagz"
 	"github.com/alicenet/utilities/internal/handler"
 	"github.com/alicenet/utilities/internal/logz"
+	"github.com/alicenet/utilities/internal/migrations"
 	"github.com/alicenet/utilities/internal/service"
 	"github.com/alicenet/utilities/internal/service/frontend"
+	"github.com/alicenet/utilities/internal/store"
 )
 
 const (
-	defaultPort  = 8080
-	httpTimeouts = 10 * time.Second
+	defaultPort             = 8080
+	httpTimeouts            = 10 * time.Second
+	defaultCacheSize        = 10000
+	defaultCacheTTL         = time.Hour
+	defaultCacheNegativeTTL = 5 * time.Second
 )
 
+var (
+	errStalenessFormat = errors.New(`staleness must be "strong", "exact:<duration>" or "max:<duration>"`)
+	errTokenFormat     = errors.New(`webhook tokens must be formatted as "<owner> <token>" lines`)
+)
+
+// stalenessFlag chooses the timestamp bound of Spanner reads.
+type stalenessFlag struct {
+	value string
+	bound spanner.TimestampBound
+}
+
+// Set the bound from "strong", "exact:<duration>" or "max:<duration>".
+func (s *stalenessFlag) Set(v string) error {
+	if v == "strong" {
+		s.value, s.bound = v, spanner.StrongRead()
+
+		return nil
+	}
+
+	mode, raw, ok := strings.Cut(v, ":")
+	if !ok {
+		return errStalenessFormat
+	}
+
+	staleness, err := time.ParseDuration(raw)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errStalenessFormat, err)
+	}
+
+	switch mode {
+	case "exact":
+		s.bound = spanner.ExactStaleness(staleness)
+	case "max":
+		s.bound = spanner.MaxStaleness(staleness)
+	default:
+		return errStalenessFormat
+	}
+
+	s.value = v
+
+	return nil
+}
+
+// String of the staleness set.
+func (s *stalenessFlag) String() string {
+	return s.value
+}
+
+// loadWebhookTokens from a file of "<owner> <token>" lines, mapping each token to its owner. Blank lines and lines
+// starting with # are ignored.
+func loadWebhookTokens(path string) (map[string]string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("webhook tokens: %w", err)
+	}
+
+	tokens := make(map[string]string)
+
+	for i, line := range strings.Split(string(raw), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: %w", path, i+1, errTokenFormat)
+		}
+
+		tokens[fields[1]] = fields[0]
+	}
+
+	return tokens, nil
+}
+
 func main() {
 	logz.Notice("starting up")
 
 	port := flag.Uint64("port", defaultPort, "port to listen on")
-	database := flag.String("database", "projects/mn-test-298216/instances/alicenet/databases/indexer", "spanner database")
+	backend := flag.String("backend", "spanner", "database backend: spanner, postgres or sqlite")
+	database := flag.String(
+		"database", "projects/mn-test-298216/instances/alicenet/databases/indexer",
+		"spanner database, postgres URL or sqlite file")
+	metrics := flag.Bool("exportmetrics", false, "whether or not to export metrics")
+	cache := flag.String("cache", "", "comma separated tables to cache reads from, such as Blocks,TransactionInputs")
+	cacheSize := flag.Int("cache-size", defaultCacheSize, "most items cached for each table")
+	cacheTTL := flag.Duration("cache-ttl", defaultCacheTTL, "how long cached items are kept")
+	cacheNegativeTTL := flag.Duration(
+		"cache-negative-ttl", defaultCacheNegativeTTL, "how long keys that weren't found are remembered")
+	cacheReadTimeout := flag.Duration(
+		"cache-read-timeout", store.DefaultCacheReadTimeout, "timeout for reads shared by concurrent requests")
+
+	pollInterval := flag.Duration(
+		"poll-interval", frontend.DefaultPollInterval, "how often subscriptions check for newly indexed data")
+	webhookTokensFile := flag.String(
+		"webhook-tokens-file", "", `file of "<owner> <token>" lines authorizing webhook management, unset disables it`)
+	staleness := stalenessFlag{value: "strong", bound: spanner.StrongRead()}
+	flag.Var(&staleness, "staleness", `spanner read staleness: "strong", "exact:<duration>" or "max:<duration>"`)
 
 	flagz.Parse()
 
@@ -47,20 +149,105 @@ func main() {
 
 	logz.Info("creating GRPC server")
 
-	ctx, grpcServer := service.NewServer()
+	ctx, grpcServer := service.NewServer(grpc.ChainUnaryInterceptor(frontend.ReadTimestamps))
 
-	logz.WithDetail("database", *database).Info("connecting to spanner")
+	if *metrics {
+		logz.Info("setting up metrics exporter")
 
-	spannerClient, err := spanner.NewClient(ctx, *database)
-	if err != nil {
-		logz.WithDetail("err", err).Criticalf("could not conect to spanner: %v", err)
-		panic(err)
+		exporter, err := stackdriver.NewExporter(stackdriver.Options{})
+		if err != nil {
+			panic(err)
+		}
+
+		defer exporter.Flush()
+
+		if err := exporter.StartMetricsExporter(); err != nil {
+			panic(err)
+		}
+
+		defer exporter.StopMetricsExporter()
 	}
 
-	defer spannerClient.Close()
+	var stores *alicenet.Stores
+
+	switch *backend {
+	case "spanner":
+		logz.WithDetail("database", *database).Info("connecting to spanner")
+
+		spannerClient, err := spanner.NewClient(ctx, *database)
+		if err != nil {
+			logz.WithDetail("err", err).Criticalf("could not conect to spanner: %v", err)
+			panic(err)
+		}
+
+		defer spannerClient.Close()
+
+		stores = alicenet.InSpanner(spannerClient, store.WithTimestampBound(staleness.bound))
+	case "postgres":
+		logz.Info("connecting to postgres")
+
+		db, err := sql.Open("postgres", *database)
+		if err != nil {
+			logz.WithDetail("err", err).Criticalf("could not connect to postgres: %v", err)
+			panic(err)
+		}
+
+		defer db.Close()
+
+		stores = alicenet.InPostgres(db)
+	case "sqlite":
+		logz.WithDetail("database", *database).Info("opening sqlite")
+
+		db, err := store.OpenSQLite(*database)
+		if err != nil {
+			logz.WithDetail("err", err).Criticalf("could not open sqlite: %v", err)
+			panic(err)
+		}
+
+		defer db.Close()
+
+		if err := migrations.RunSQLiteMigrations(db); err != nil {
+			logz.WithDetail("err", err).Criticalf("could not migrate sqlite: %v", err)
+			panic(err)
+		}
+
+		stores = alicenet.InSQLite(db)
+	default:
+		logz.WithDetail("backend", *backend).Critical("unknown backend")
+		panic("unknown backend: " + *backend)
+	}
+
+	if *cache != "" {
+		logz.WithDetail("tables", *cache).Info("caching reads")
+
+		opts := store.CacheOptions{
+			Size:        *cacheSize,
+			TTL:         *cacheTTL,
+			NegativeTTL: *cacheNegativeTTL,
+			ReadTimeout: *cacheReadTimeout,
+		}
+		if err := stores.Cache(opts, strings.Split(*cache, ",")...); err != nil {
+			logz.WithDetail("err", err).Criticalf("could not cache reads: %v", err)
+			panic(err)
+		}
+	}
+
+	var webhookTokens map[string]string
+
+	if *webhookTokensFile != "" {
+		tokens, err := loadWebhookTokens(*webhookTokensFile)
+		if err != nil {
+			logz.WithDetail("err", err).Criticalf("could not load webhook tokens: %v", err)
+			panic(err)
+		}
+
+		logz.WithDetail("tokens", len(tokens)).Info("webhook management enabled")
+
+		webhookTokens = tokens
+	}
 
-	stores := alicenet.InSpanner(spannerClient)
-	service := frontend.NewService(stores)
+	service := frontend.NewService(
+		stores, frontend.WithPollInterval(*pollInterval), frontend.WithWebhookTokens(webhookTokens))
 	mux := runtime.NewServeMux()
 
 	alicev1.RegisterAliceServiceServer(grpcServer, service)
@@ -0,0 +1,144 @@
+/*
+Notifier delivers events from the alicenet indexer to registered webhooks.
+
+It follows blocks in a shared Spanner, Postgres or SQLite database populated by the indexer worker process, and
+posts signed events for them to the webhooks created through the indexer frontend.
+*/
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"cloud.google.com/go/spanner"
+	"contrib.go.opencensus.io/exporter/stackdriver"
+	_ "github.com/lib/pq"
+
+	"github.com/alicenet/utilities/internal/alicenet"
+	"github.com/alicenet/utilities/internal/flagz"
+	"github.com/alicenet/utilities/internal/logz"
+	"github.com/alicenet/utilities/internal/migrations"
+	"github.com/alicenet/utilities/internal/service/frontend"
+	"github.com/alicenet/utilities/internal/service/notifier"
+	"github.com/alicenet/utilities/internal/store"
+)
+
+func main() {
+	logz.Notice("starting up")
+
+	backend := flag.String("backend", "spanner", "database backend: spanner, postgres or sqlite")
+	database := flag.String(
+		"database", "projects/mn-test-298216/instances/alicenet/databases/indexer",
+		"spanner database, postgres URL or sqlite file")
+	metrics := flag.Bool("exportmetrics", false, "whether or not to export metrics")
+	pollInterval := flag.Duration("poll-interval", frontend.DefaultPollInterval, "how often to check for new blocks")
+	deliveryAttempts := flag.Int(
+		"delivery-attempts", notifier.DefaultDeliveryPolicy.Attempts, "attempts per event before it is abandoned")
+	deliveryInitial := flag.Duration(
+		"delivery-initial-backoff", notifier.DefaultDeliveryPolicy.InitialBackoff, "initial backoff between attempts")
+	deliveryMax := flag.Duration(
+		"delivery-max-backoff", notifier.DefaultDeliveryPolicy.MaxBackoff, "maximum backoff between attempts")
+	deliveryTimeout := flag.Duration(
+		"delivery-timeout", notifier.DefaultDeliveryPolicy.Timeout, "timeout for each delivery attempt")
+
+	flagz.Parse()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		logz.Debug("waiting on signals")
+
+		s := <-signals
+		logz.WithDetail("signal", s).Info("got shutdown signal")
+
+		cancel()
+	}()
+
+	if *metrics {
+		logz.Info("setting up metrics exporter")
+
+		exporter, err := stackdriver.NewExporter(stackdriver.Options{})
+		if err != nil {
+			panic(err)
+		}
+
+		defer exporter.Flush()
+
+		if err := exporter.StartMetricsExporter(); err != nil {
+			panic(err)
+		}
+
+		defer exporter.StopMetricsExporter()
+	}
+
+	var stores *alicenet.Stores
+
+	switch *backend {
+	case "spanner":
+		logz.WithDetail("database", *database).Info("connecting to spanner")
+
+		spannerClient, err := spanner.NewClient(ctx, *database)
+		if err != nil {
+			logz.WithDetail("err", err).Criticalf("could not conect to spanner: %v", err)
+			panic(err)
+		}
+
+		defer spannerClient.Close()
+
+		stores = alicenet.InSpanner(spannerClient)
+	case "postgres":
+		logz.Info("connecting to postgres")
+
+		db, err := sql.Open("postgres", *database)
+		if err != nil {
+			logz.WithDetail("err", err).Criticalf("could not connect to postgres: %v", err)
+			panic(err)
+		}
+
+		defer db.Close()
+
+		stores = alicenet.InPostgres(db)
+	case "sqlite":
+		logz.WithDetail("database", *database).Info("opening sqlite")
+
+		db, err := store.OpenSQLite(*database)
+		if err != nil {
+			logz.WithDetail("err", err).Criticalf("could not open sqlite: %v", err)
+			panic(err)
+		}
+
+		defer db.Close()
+
+		if err := migrations.RunSQLiteMigrations(db); err != nil {
+			logz.WithDetail("err", err).Criticalf("could not migrate sqlite: %v", err)
+			panic(err)
+		}
+
+		stores = alicenet.InSQLite(db)
+	default:
+		logz.WithDetail("backend", *backend).Critical("unknown backend")
+		panic("unknown backend: " + *backend)
+	}
+
+	notifier := notifier.New(
+		stores,
+		notifier.WithPollInterval(*pollInterval),
+		notifier.WithDeliveryPolicy(notifier.DeliveryPolicy{
+			Attempts:       *deliveryAttempts,
+			InitialBackoff: *deliveryInitial,
+			MaxBackoff:     *deliveryMax,
+			Timeout:        *deliveryTimeout,
+		}),
+	)
+
+	notifier.Run(ctx)
+
+	logz.Notice("shutting down")
+}
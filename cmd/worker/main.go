@@ -1,32 +1,140 @@
 /*
 Worker continuously scans alicenet to index blocks and transactions.
 
-It populates a shared Spanner database that the indexer frontend serves from.
+It populates a shared Spanner, Postgres or SQLite database that the indexer frontend serves from.
 */
 package main
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"errors"
 	"flag"
+	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
 	"cloud.google.com/go/spanner"
 	"contrib.go.opencensus.io/exporter/stackdriver"
+	_ "github.com/lib/pq"
+	"go.opencensus.io/plugin/ocgrpc"
+	"go.opencensus.io/stats/view"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
 
 	"github.com/alicenet/utilities/internal/alicenet"
 	"github.com/alicenet/utilities/internal/flagz"
 	"github.com/alicenet/utilities/internal/logz"
+	"github.com/alicenet/utilities/internal/migrations"
 	"github.com/alicenet/utilities/internal/service/worker"
+	"github.com/alicenet/utilities/internal/store"
 )
 
+const (
+	defaultRetryAttempts    = 5
+	defaultRetryInitial     = 250 * time.Millisecond
+	defaultRetryMax         = 10 * time.Second
+	defaultRequestTimeout   = 30 * time.Second
+	defaultBreakerThreshold = 10
+	defaultBreakerCooldown  = 30 * time.Second
+)
+
+var (
+	errHeaderFormat  = errors.New(`header must be formatted as "Key: Value"`)
+	errNoCertificate = errors.New("no certificates found")
+)
+
+// headerFlags collects repeated "Key: Value" flags into headers.
+type headerFlags [][2]string
+
+// Set a header from a "Key: Value" flag.
+func (h *headerFlags) Set(v string) error {
+	key, value, ok := strings.Cut(v, ":")
+	if !ok {
+		return errHeaderFormat
+	}
+
+	*h = append(*h, [2]string{strings.TrimSpace(key), strings.TrimSpace(value)})
+
+	return nil
+}
+
+// String of all headers set.
+func (h *headerFlags) String() string {
+	return fmt.Sprint([][2]string(*h))
+}
+
+// loadTLS certificate authorities and client certificates from PEM files. Empty paths are ignored.
+func loadTLS(caFile, certFile, keyFile string) (*x509.CertPool, []tls.Certificate, error) {
+	var (
+		pool  *x509.CertPool
+		certs []tls.Certificate
+	)
+
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("ca bundle: %w", err)
+		}
+
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, nil, fmt.Errorf("ca bundle %s: %w", caFile, errNoCertificate)
+		}
+	}
+
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("client certificate: %w", err)
+		}
+
+		certs = append(certs, cert)
+	}
+
+	return pool, certs, nil
+}
+
 func main() {
 	logz.Notice("starting up")
 
 	api := flag.String("api", "edge.staging.alice.net", "api hosting alicenet")
-	database := flag.String("database", "projects/mn-test-298216/instances/alicenet/databases/indexer", "spanner database")
+	transport := flag.String("transport", "http", "transport used to reach the api: http or grpc")
+	grpcInsecure := flag.Bool("grpc-insecure", false, "connect to the grpc api without TLS")
+	apiToken := flag.String("api-token", "", "bearer token sent to the api")
+	userAgent := flag.String("user-agent", "alicenet-indexer", "user agent sent to the api")
+	caFile := flag.String("ca-file", "", "PEM bundle of certificate authorities to verify the api with")
+	certFile := flag.String("cert-file", "", "PEM client certificate for mutual TLS with the api")
+	keyFile := flag.String("key-file", "", "PEM client key for mutual TLS with the api")
+
+	var headers headerFlags
+
+	flag.Var(&headers, "api-header", `header sent to the api as "Key: Value", may be repeated`)
+	backend := flag.String("backend", "spanner", "database backend: spanner, postgres or sqlite")
+	database := flag.String(
+		"database", "projects/mn-test-298216/instances/alicenet/databases/indexer",
+		"spanner database, postgres URL or sqlite file")
 	metrics := flag.Bool("exportmetrics", false, "whether or not to export metrics")
+	fetchConcurrency := flag.Int("fetch-concurrency", worker.DefaultFetchConcurrency, "concurrent requests to alicenet")
+	retryAttempts := flag.Int("retry-attempts", defaultRetryAttempts, "attempts per alicenet request")
+	retryInitial := flag.Duration("retry-initial-backoff", defaultRetryInitial, "initial backoff between retries")
+	retryMax := flag.Duration("retry-max-backoff", defaultRetryMax, "maximum backoff between retries")
+	requestTimeout := flag.Duration("request-timeout", defaultRequestTimeout, "timeout for each alicenet request attempt")
+	breakerThreshold := flag.Int("breaker-threshold", defaultBreakerThreshold, "errors before pausing requests")
+	breakerCooldown := flag.Duration("breaker-cooldown", defaultBreakerCooldown, "how long to pause requests once tripped")
+	startHeight := flag.Int("start-height", 0, "height to start indexing from, if above the last block indexed")
+	reconcileInterval := flag.Duration(
+		"reconcile-interval", worker.DefaultReconcilePolicy.Interval, "how often to retry missing transactions, 0 disables")
+	reconcileAttempts := flag.Int64(
+		"reconcile-max-attempts", worker.DefaultReconcilePolicy.MaxAttempts, "attempts per missing transaction")
+	reconcileBatch := flag.Int64(
+		"reconcile-batch", worker.DefaultReconcilePolicy.BatchSize, "missing transactions retried per pass")
 
 	flagz.Parse()
 
@@ -59,21 +167,152 @@ func main() {
 		}
 
 		defer exporter.StopMetricsExporter()
+
+		// Calls made by the GRPC client are only recorded once their views are registered.
+		if err := view.Register(ocgrpc.DefaultClientViews...); err != nil {
+			panic(err)
+		}
 	}
 
-	logz.WithDetail("database", *database).Info("connecting to spanner")
+	var stores *alicenet.Stores
+
+	switch *backend {
+	case "spanner":
+		logz.WithDetail("database", *database).Info("connecting to spanner")
+
+		spannerClient, err := spanner.NewClient(ctx, *database)
+		if err != nil {
+			logz.WithDetail("err", err).Criticalf("could not conect to spanner: %v", err)
+			panic(err)
+		}
+
+		defer spannerClient.Close()
+
+		stores = alicenet.InSpanner(spannerClient)
+	case "postgres":
+		logz.Info("connecting to postgres")
+
+		db, err := sql.Open("postgres", *database)
+		if err != nil {
+			logz.WithDetail("err", err).Criticalf("could not connect to postgres: %v", err)
+			panic(err)
+		}
+
+		defer db.Close()
+
+		stores = alicenet.InPostgres(db)
+	case "sqlite":
+		logz.WithDetail("database", *database).Info("opening sqlite")
+
+		db, err := store.OpenSQLite(*database)
+		if err != nil {
+			logz.WithDetail("err", err).Criticalf("could not open sqlite: %v", err)
+			panic(err)
+		}
+
+		defer db.Close()
+
+		if err := migrations.RunSQLiteMigrations(db); err != nil {
+			logz.WithDetail("err", err).Criticalf("could not migrate sqlite: %v", err)
+			panic(err)
+		}
 
-	spannerClient, err := spanner.NewClient(ctx, *database)
+		stores = alicenet.InSQLite(db)
+	default:
+		logz.WithDetail("backend", *backend).Critical("unknown backend")
+		panic("unknown backend: " + *backend)
+	}
+
+	rootCAs, clientCerts, err := loadTLS(*caFile, *certFile, *keyFile)
 	if err != nil {
-		logz.WithDetail("err", err).Criticalf("could not conect to spanner: %v", err)
+		logz.WithDetail("err", err).Criticalf("could not load TLS files: %v", err)
 		panic(err)
 	}
 
-	defer spannerClient.Close()
+	var alicenetClient alicenet.Interface
+
+	switch *transport {
+	case "http":
+		opts := []alicenet.Option{
+			alicenet.WithRetries(*retryAttempts, *retryInitial, *retryMax),
+			alicenet.WithRequestTimeout(*requestTimeout),
+			alicenet.WithCircuitBreaker(*breakerThreshold, *breakerCooldown),
+			alicenet.WithUserAgent(*userAgent),
+			alicenet.WithClientCertificates(clientCerts...),
+		}
+
+		if rootCAs != nil {
+			opts = append(opts, alicenet.WithRootCAs(rootCAs))
+		}
+
+		if *apiToken != "" {
+			opts = append(opts, alicenet.WithBearerToken(*apiToken))
+		}
+
+		for _, h := range headers {
+			opts = append(opts, alicenet.WithHeader(h[0], h[1]))
+		}
+
+		httpClient, err := alicenet.Connect(*api, opts...)
+		if err != nil {
+			logz.WithDetail("err", err).Criticalf("could not connect to alicenet: %v", err)
+			panic(err)
+		}
+
+		alicenetClient = httpClient
+	case "grpc":
+		creds := credentials.NewTLS(&tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			RootCAs:      rootCAs,
+			Certificates: clientCerts,
+		})
+		if *grpcInsecure {
+			creds = insecure.NewCredentials()
+		}
+
+		var md []string
+
+		for _, h := range headers {
+			md = append(md, strings.ToLower(h[0]), h[1])
+		}
+
+		if *apiToken != "" {
+			md = append(md, "authorization", "Bearer "+*apiToken)
+		}
+
+		grpcClient, err := alicenet.DialGRPC(
+			*api,
+			grpc.WithTransportCredentials(creds),
+			grpc.WithUserAgent(*userAgent),
+			alicenet.WithGRPCMetadata(md...),
+			alicenet.WithGRPCRetries(*retryAttempts, *retryInitial, *retryMax),
+			alicenet.WithGRPCCircuitBreaker(*breakerThreshold, *breakerCooldown),
+			alicenet.WithGRPCRequestTimeout(*requestTimeout),
+		)
+		if err != nil {
+			logz.WithDetail("err", err).Criticalf("could not dial alicenet: %v", err)
+			panic(err)
+		}
+
+		defer grpcClient.Close()
+
+		alicenetClient = grpcClient
+	default:
+		logz.WithDetail("transport", *transport).Critical("unknown transport")
+		panic("unknown transport: " + *transport)
+	}
 
-	alicenetClient := alicenet.Connect(*api)
-	stores := alicenet.InSpanner(spannerClient)
-	worker := worker.New(alicenetClient, stores)
+	worker := worker.New(
+		alicenetClient,
+		stores,
+		worker.WithStartHeight(*startHeight),
+		worker.WithFetchConcurrency(*fetchConcurrency),
+		worker.WithReconcilePolicy(worker.ReconcilePolicy{
+			Interval:    *reconcileInterval,
+			MaxAttempts: *reconcileAttempts,
+			BatchSize:   *reconcileBatch,
+		}),
+	)
 
 	worker.Run(ctx)
 
@@ -4,10 +4,16 @@ package alicenet
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
 	"sync"
 	"time"
 
@@ -29,10 +35,20 @@ const (
 //nolint:gochecknoglobals // Needed for metrics
 var metricsSetup sync.Once
 
+// ErrInvalidURL is returned when connecting to a base URL that can't be used to reach alicenet.
+var ErrInvalidURL = errors.New("invalid base url")
+
+// ErrUnknownTable indicates a table name that isn't one of the Stores.
+var ErrUnknownTable = errors.New("unknown table")
+
+// ErrUncachedTable indicates a table whose rows change after they are written, so reads from it can't be cached.
+var ErrUncachedTable = errors.New("table can't be cached")
+
 // An APIError returned from alicenet.
 type APIError struct {
-	Status  string
-	Message []byte
+	Status     string
+	StatusCode int
+	Message    []byte
 }
 
 // Error reported from the API that isn't a low-level socket error.
@@ -46,51 +62,189 @@ func (a APIError) Error() string {
 type Interface interface {
 	Height(context.Context) (uint32, error)
 	BlockHeader(context.Context, uint32) (*proto.BlockHeader, error)
-	Transaction(context.Context, string) (*MinedTransactionResponse, error)
+	Transaction(context.Context, string) (*proto.MinedTransactionResponse, error)
 }
 
 // Client to interact with alicenet.
-// Note: This is a temporary solution as the GRPC endpoint is currently unavailable.
+// Note: This is a temporary solution for nodes that don't expose GRPC. Prefer GRPCClient where it is available.
 type Client struct {
-	baseURL string
-	client  *http.Client
+	baseURL   *url.URL
+	client    *http.Client
+	retry     retryPolicy
+	timeout   time.Duration
+	breaker   *breaker
+	header    http.Header
+	tlsConfig *tls.Config
 }
 
 // Ensure Client matches the package Interface.
 var _ Interface = &Client{}
 
-// Connect to AliceNet.
-func Connect(baseURL string) *Client {
+// An Option to configure the Client.
+type Option func(*Client)
+
+// WithRetries of transient errors, up to attempts in total, waiting a jittered exponential backoff between
+// each that starts at initial and is capped at max.
+func WithRetries(attempts int, initial, max time.Duration) Option {
+	return func(c *Client) {
+		c.retry = retryPolicy{attempts: attempts, initial: initial, max: max}
+	}
+}
+
+// WithRequestTimeout bounds how long each individual attempt may take.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithCircuitBreaker stops sending requests for cooldown after threshold consecutive transient errors.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(c *Client) {
+		c.breaker = &breaker{threshold: threshold, cooldown: cooldown}
+	}
+}
+
+// WithHeader sets a header on every request, such as an API key required by a gateway.
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		c.header.Add(key, value)
+	}
+}
+
+// WithBearerToken authorizes every request with the given token.
+func WithBearerToken(token string) Option {
+	return WithHeader("Authorization", "Bearer "+token)
+}
+
+// WithUserAgent identifies the client on every request.
+func WithUserAgent(userAgent string) Option {
+	return WithHeader("User-Agent", userAgent)
+}
+
+// WithRootCAs verifies the server certificate against the given pool instead of the system roots.
+func WithRootCAs(pool *x509.CertPool) Option {
+	return func(c *Client) {
+		c.tlsConfig.RootCAs = pool
+	}
+}
+
+// WithClientCertificates presented to the server for mutual TLS.
+func WithClientCertificates(certs ...tls.Certificate) Option {
+	return func(c *Client) {
+		c.tlsConfig.Certificates = append(c.tlsConfig.Certificates, certs...)
+	}
+}
+
+// Connect to AliceNet. The base URL may be a bare host, which is reached over https, or a full URL including the
+// scheme and any path prefix the API is hosted under.
+func Connect(baseURL string, opts ...Option) (*Client, error) {
 	metricsSetup.Do(func() {
 		if err := view.Register(ochttp.DefaultClientViews...); err != nil {
 			panic(err)
 		}
 	})
 
-	octr := &ochttp.Transport{}
+	base, err := parseBaseURL(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+
+	c := &Client{
+		baseURL:   base,
+		retry:     retryPolicy{attempts: 1},
+		breaker:   &breaker{},
+		header:    make(http.Header),
+		tlsConfig: &tls.Config{MinVersion: tls.VersionTLS12},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	//nolint:forcetypeassert // The default transport is always an *http.Transport.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = c.tlsConfig
 
-	return &Client{baseURL: baseURL, client: &http.Client{Transport: octr}}
+	c.client = &http.Client{Transport: &ochttp.Transport{Base: transport}}
+
+	return c, nil
 }
 
-// do handles boilerplate of calling the alicenet local state API.
-func do[In, Out any](ctx context.Context, client *http.Client, base, path string, request In) (Out, error) {
-	url := fmt.Sprintf("https://%s/%s", base, path)
+// parseBaseURL from either a bare host or a full http(s) URL.
+func parseBaseURL(raw string) (*url.URL, error) {
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
 
-	var out Out
+	base, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+
+	if base.Scheme != "http" && base.Scheme != "https" {
+		return nil, fmt.Errorf("%w: unsupported scheme %q", ErrInvalidURL, base.Scheme)
+	}
+
+	if base.Host == "" {
+		return nil, fmt.Errorf("%w: missing host in %q", ErrInvalidURL, raw)
+	}
 
-	buf := new(bytes.Buffer)
-	encoder := json.NewEncoder(buf)
+	return base, nil
+}
+
+// do handles boilerplate of calling the alicenet local state API, retrying transient errors.
+func do[In, Out any](ctx context.Context, c *Client, path string, request In) (Out, error) {
+	var out Out
 
-	if err := encoder.Encode(request); err != nil {
+	body, err := json.Marshal(request)
+	if err != nil {
 		return out, fmt.Errorf("encode: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, buf)
+	for attempt := 1; ; attempt++ {
+		if err := c.breaker.allow(); err != nil {
+			return out, err
+		}
+
+		out, err = once[Out](ctx, c, path, body)
+
+		c.breaker.record(ctx, err)
+
+		if err == nil || !Transient(err) || attempt >= c.retry.attempts || ctx.Err() != nil {
+			return out, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return out, fmt.Errorf("retry: %w", ctx.Err())
+		case <-time.After(c.retry.backoff(attempt)):
+		}
+	}
+}
+
+// once makes a single attempt at calling the alicenet local state API.
+func once[Out any](ctx context.Context, c *Client, path string, body []byte) (Out, error) {
+	endpoint := c.baseURL.JoinPath(path).String()
+
+	var out Out
+
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
 	if err != nil {
 		return out, fmt.Errorf("request: %w", err)
 	}
 
-	rawResp, err := client.Do(req)
+	req.Header = c.header.Clone()
+	req.Header.Set("Content-Type", "application/json")
+
+	rawResp, err := c.client.Do(req)
 	if err != nil {
 		return out, fmt.Errorf("response: %w", err)
 	}
@@ -98,7 +252,7 @@ func do[In, Out any](ctx context.Context, client *http.Client, base, path string
 
 	if rawResp.StatusCode != http.StatusOK {
 		msg, _ := io.ReadAll(rawResp.Body)
-		err := APIError{Status: rawResp.Status, Message: msg}
+		err := APIError{Status: rawResp.Status, StatusCode: rawResp.StatusCode, Message: msg}
 
 		return out, fmt.Errorf("response: %w", err)
 	}
@@ -117,7 +271,7 @@ func (c *Client) Height(ctx context.Context) (uint32, error) {
 	resp, err := do[
 		proto.BlockNumberRequest,
 		proto.BlockNumberResponse,
-	](ctx, c.client, c.baseURL, blockNumberPath, proto.BlockNumberRequest{})
+	](ctx, c, blockNumberPath, proto.BlockNumberRequest{})
 	if err != nil {
 		return 0, fmt.Errorf("height: %w", err)
 	}
@@ -130,7 +284,7 @@ func (c *Client) BlockHeader(ctx context.Context, height uint32) (*proto.BlockHe
 	resp, err := do[
 		proto.BlockHeaderRequest,
 		proto.BlockHeaderResponse,
-	](ctx, c.client, c.baseURL, blockHeaderPath, proto.BlockHeaderRequest{Height: height})
+	](ctx, c, blockHeaderPath, proto.BlockHeaderRequest{Height: height})
 	if err != nil {
 		return nil, fmt.Errorf("header: %w", err)
 	}
@@ -139,63 +293,139 @@ func (c *Client) BlockHeader(ctx context.Context, height uint32) (*proto.BlockHe
 }
 
 // Transaction for a given hash.
-func (c *Client) Transaction(ctx context.Context, hash string) (*MinedTransactionResponse, error) {
+func (c *Client) Transaction(ctx context.Context, hash string) (*proto.MinedTransactionResponse, error) {
 	resp, err := do[
 		proto.MinedTransactionRequest,
-		MinedTransactionResponse,
-	](ctx, c.client, c.baseURL, minedTransactionPath, proto.MinedTransactionRequest{TxHash: hash})
+		minedTransactionResponse,
+	](ctx, c, minedTransactionPath, proto.MinedTransactionRequest{TxHash: hash})
 	if err != nil {
 		return nil, fmt.Errorf("transaction: %w", err)
 	}
 
-	return &resp, nil
+	return resp.toProto(), nil
 }
 
-// MinedTransactionResponse is a hack put in place to allow for json deserialization because the proto models will
+// minedTransactionResponse is a hack put in place to allow for json deserialization because the proto models will
 // eat the contents of Vout. This will not be necessary after moving to using GRPC clients.
-type MinedTransactionResponse struct {
+type minedTransactionResponse struct {
 	Tx struct {
-		Fee string
-		Vin []struct {
-			TXInLinker struct {
-				TXInPreImage struct {
-					ChainID        uint32
-					ConsumedTxIdx  uint32
-					ConsumedTxHash string
-				}
-				TxHash string
-			}
-			Signature string
+		Fee  string
+		Vin  []minedTransactionInput
+		Vout []minedTransactionOutput
+	}
+}
+
+// A minedTransactionInput consumed by a minedTransactionResponse.
+type minedTransactionInput struct {
+	TXInLinker struct {
+		TXInPreImage struct {
+			ChainID        uint32
+			ConsumedTxIdx  uint32
+			ConsumedTxHash string
 		}
-		Vout []struct {
-			DataStore *struct {
-				DSLinker struct {
-					DSPreImage struct {
-						ChainID  uint32
-						Index    string
-						IssuedAt uint32
-						Deposit  string
-						RawData  string
-						TXOutIdx uint32
-						Owner    string
-						Fee      string
-					}
-					TxHash string
-				}
-				Signature string
-			}
-			ValueStore *struct {
-				VSPreImage struct {
-					ChainID  uint32
-					Value    string
-					TXOutIdx uint32
-					Owner    string
-					Fee      string
-				}
-				TxHash string
-			}
+		TxHash string
+	}
+	Signature string
+}
+
+// A minedTransactionOutput created by a minedTransactionResponse, holding at most one of its stores.
+type minedTransactionOutput struct {
+	DataStore  *minedDataStore
+	ValueStore *minedValueStore
+}
+
+// A minedDataStore output.
+type minedDataStore struct {
+	DSLinker struct {
+		DSPreImage struct {
+			ChainID  uint32
+			Index    string
+			IssuedAt uint32
+			Deposit  string
+			RawData  string
+			TXOutIdx uint32
+			Owner    string
+			Fee      string
 		}
+		TxHash string
 	}
+	Signature string
+}
+
+// A minedValueStore output.
+type minedValueStore struct {
+	VSPreImage struct {
+		ChainID  uint32
+		Value    string
+		TXOutIdx uint32
+		Owner    string
+		Fee      string
+	}
+	TxHash string
+}
+
+// toProto converts the decoded transaction into the proto models returned by the GRPC client.
+func (m *minedTransactionResponse) toProto() *proto.MinedTransactionResponse {
+	tx := &proto.Tx{Fee: m.Tx.Fee}
+
+	for _, vin := range m.Tx.Vin {
+		preImage := vin.TXInLinker.TXInPreImage
+
+		tx.Vin = append(tx.Vin, &proto.TXIn{
+			TXInLinker: &proto.TXInLinker{
+				TXInPreImage: &proto.TXInPreImage{
+					ChainID:        preImage.ChainID,
+					ConsumedTxIdx:  preImage.ConsumedTxIdx,
+					ConsumedTxHash: preImage.ConsumedTxHash,
+				},
+				TxHash: vin.TXInLinker.TxHash,
+			},
+			Signature: vin.Signature,
+		})
+	}
+
+	for _, vout := range m.Tx.Vout {
+		output := &proto.TXOut{}
+
+		if ds := vout.DataStore; ds != nil {
+			preImage := ds.DSLinker.DSPreImage
+			output.Utxo = &proto.TXOut_DataStore{DataStore: &proto.DataStore{
+				DSLinker: &proto.DSLinker{
+					DSPreImage: &proto.DSPreImage{
+						ChainID:  preImage.ChainID,
+						Index:    preImage.Index,
+						IssuedAt: preImage.IssuedAt,
+						Deposit:  preImage.Deposit,
+						RawData:  preImage.RawData,
+						TXOutIdx: preImage.TXOutIdx,
+						Owner:    preImage.Owner,
+						Fee:      preImage.Fee,
+					},
+					TxHash: ds.DSLinker.TxHash,
+				},
+				Signature: ds.Signature,
+			}}
+		}
+
+		if vs := vout.ValueStore; vs != nil {
+			preImage := vs.VSPreImage
+			output.Utxo = &proto.TXOut_ValueStore{ValueStore: &proto.ValueStore{
+				VSPreImage: &proto.VSPreImage{
+					ChainID:  preImage.ChainID,
+					Value:    preImage.Value,
+					TXOutIdx: preImage.TXOutIdx,
+					Owner:    preImage.Owner,
+					Fee:      preImage.Fee,
+				},
+				TxHash: vs.TxHash,
+			}}
+		}
+
+		// Other outputs, such as atomic swaps, are kept with neither store so output indexes stay aligned.
+		tx.Vout = append(tx.Vout, output)
+	}
+
+	return &proto.MinedTransactionResponse{Tx: tx}
 }
 
 // A Block model for storage in Spanner.
@@ -213,8 +443,8 @@ type Block struct {
 }
 
 // Key for the Block.
-func (b Block) Key() spanner.Key {
-	return spanner.Key{b.Height}
+func (b Block) Key() store.Key {
+	return store.Key{b.Height}
 }
 
 // Table to store Blocks.
@@ -222,13 +452,32 @@ func (Block) Table() string {
 	return "Blocks"
 }
 
-// List statement for Blocks.
-func (Block) List(_ spanner.Key, limit, offset int64) spanner.Statement {
-	stmt := spanner.NewStatement("SELECT * FROM Blocks ORDER BY Height DESC LIMIT @limit OFFSET @offset")
-	stmt.Params["limit"] = limit
-	stmt.Params["offset"] = offset
+// List query for Blocks, from the highest.
+func (Block) List(_ store.Key, limit, offset int64) store.Query {
+	return store.Query{Order: []store.Order{store.Descending("Height")}, Limit: limit, Offset: offset}
+}
+
+// BlocksByHash query for the Blocks with a header root hash, or with a transaction root hash if they hold any
+// transactions, as the transaction root of an empty block is the same for every one. Served by the
+// BlocksByHeaderRootHash and BlocksByTransactionRootHash indexes.
+func BlocksByHash(hash string, limit int64) store.Query {
+	return store.Query{
+		Alternatives: [][]store.Filter{
+			{store.Equal("HeaderRootHash", hash)},
+			{store.Equal("TransactionRootHash", hash), store.AtLeast("TransactionCount", int64(1))},
+		},
+		Order: []store.Order{store.Ascending("Height")},
+		Limit: limit,
+	}
+}
 
-	return stmt
+// BlocksFrom query for the Blocks from a height up, from the lowest.
+func BlocksFrom(height, limit int64) store.Query {
+	return store.Query{
+		Filters: []store.Filter{store.AtLeast("Height", height)},
+		Order:   []store.Order{store.Ascending("Height")},
+		Limit:   limit,
+	}
 }
 
 // A Transaction model for storage in Spanner.
@@ -240,8 +489,8 @@ type Transaction struct {
 }
 
 // Key for the Transaction.
-func (t Transaction) Key() spanner.Key {
-	return spanner.Key{t.Height, t.TransactionHash}
+func (t Transaction) Key() store.Key {
+	return store.Key{t.TransactionHash}
 }
 
 // Table to store Transactions.
@@ -249,15 +498,13 @@ func (Transaction) Table() string {
 	return "Transactions"
 }
 
-// List statement for Transactions.
-func (Transaction) List(_ spanner.Key, limit, offset int64) spanner.Statement {
-	stmt := spanner.NewStatement(
-		"SELECT * FROM Transactions ORDER BY Height DESC, TransactionHash DESC LIMIT @limit OFFSET @offset",
-	)
-	stmt.Params["limit"] = limit
-	stmt.Params["offset"] = offset
-
-	return stmt
+// List query for Transactions.
+func (Transaction) List(_ store.Key, limit, offset int64) store.Query {
+	return store.Query{
+		Order:  []store.Order{store.Descending("Height"), store.Descending("TransactionHash")},
+		Limit:  limit,
+		Offset: offset,
+	}
 }
 
 // A TransactionInput for storage in Spanner.
@@ -272,8 +519,8 @@ type TransactionInput struct {
 }
 
 // Key for the Transaction.
-func (t TransactionInput) Key() spanner.Key {
-	return spanner.Key{t.TransactionHash, t.TransactionIndex}
+func (t TransactionInput) Key() store.Key {
+	return store.Key{t.TransactionHash, t.TransactionIndex}
 }
 
 // Table to store TransactionInputs.
@@ -281,31 +528,34 @@ func (TransactionInput) Table() string {
 	return "TransactionInputs"
 }
 
-// List statement for TransactionInputs.
-func (TransactionInput) List(prefix spanner.Key, _, _ int64) spanner.Statement {
-	stmt := spanner.NewStatement(
-		"SELECT * FROM TransactionInputs WHERE TransactionHash = @transactionHash " +
-			"ORDER BY TransactionIndex DESC",
-	)
-	stmt.Params["transactionHash"] = prefix[0]
-
-	return stmt
+// List query for TransactionInputs.
+func (TransactionInput) List(prefix store.Key, limit, offset int64) store.Query {
+	return store.Query{
+		Filters: []store.Filter{store.Equal("TransactionHash", prefix[0])},
+		Order:   []store.Order{store.Descending("TransactionIndex")},
+		Limit:   limit,
+		Offset:  offset,
+	}
 }
 
 // A ValueStore model to store in Spanner.
 type ValueStore struct {
-	TransactionHash     string
-	ChainID             int64
-	Value               string
-	TransactionOutIndex int64
-	Owner               string
-	Fee                 string
-	ObserveTime         time.Time
+	TransactionHash      string
+	ChainID              int64
+	Value                string
+	TransactionOutIndex  int64
+	Owner                string
+	Fee                  string
+	ObserveTime          time.Time
+	Spent                *bool
+	SpentTransactionHash *string
+	SpentInputIndex      *int64
+	SpentHeight          *int64
 }
 
 // Key for the ValueStores.
-func (v ValueStore) Key() spanner.Key {
-	return spanner.Key{v.TransactionHash, v.TransactionOutIndex}
+func (v ValueStore) Key() store.Key {
+	return store.Key{v.TransactionHash, v.TransactionOutIndex}
 }
 
 // Table to store ValueStores.
@@ -313,35 +563,38 @@ func (ValueStore) Table() string {
 	return "ValueStores"
 }
 
-// List statement for ValueStores.
-func (ValueStore) List(prefix spanner.Key, _, _ int64) spanner.Statement {
-	stmt := spanner.NewStatement(
-		"SELECT * FROM ValueStores WHERE TransactionHash = @transactionHash " +
-			"ORDER BY TransactionOutIndex DESC",
-	)
-	stmt.Params["transactionHash"] = prefix[0]
-
-	return stmt
+// List query for ValueStores.
+func (ValueStore) List(prefix store.Key, limit, offset int64) store.Query {
+	return store.Query{
+		Filters: []store.Filter{store.Equal("TransactionHash", prefix[0])},
+		Order:   []store.Order{store.Descending("TransactionOutIndex")},
+		Limit:   limit,
+		Offset:  offset,
+	}
 }
 
 // A DataStore model to store in Spanner.
 type DataStore struct {
-	Signature           string
-	TransactionHash     string
-	ChainID             int64
-	Index               string
-	IssuedAt            int64
-	Deposit             string
-	RawData             string
-	TransactionOutIndex int64
-	Owner               string
-	Fee                 string
-	ObserveTime         time.Time
+	Signature            string
+	TransactionHash      string
+	ChainID              int64
+	Index                string
+	IssuedAt             int64
+	Deposit              string
+	RawData              string
+	TransactionOutIndex  int64
+	Owner                string
+	Fee                  string
+	ObserveTime          time.Time
+	Spent                *bool
+	SpentTransactionHash *string
+	SpentInputIndex      *int64
+	SpentHeight          *int64
 }
 
 // Key for the DataStores.
-func (d DataStore) Key() spanner.Key {
-	return spanner.Key{d.TransactionHash, d.TransactionOutIndex}
+func (d DataStore) Key() store.Key {
+	return store.Key{d.TransactionHash, d.TransactionOutIndex}
 }
 
 // Table to store DataStores.
@@ -349,15 +602,14 @@ func (DataStore) Table() string {
 	return "DataStores"
 }
 
-// List statement for DataStores.
-func (DataStore) List(prefix spanner.Key, _, _ int64) spanner.Statement {
-	stmt := spanner.NewStatement(
-		"SELECT * FROM DataStores WHERE TransactionHash = @transactionHash " +
-			"ORDER BY TransactionOutIndex DESC",
-	)
-	stmt.Params["transactionHash"] = prefix[0]
-
-	return stmt
+// List query for DataStores.
+func (DataStore) List(prefix store.Key, limit, offset int64) store.Query {
+	return store.Query{
+		Filters: []store.Filter{store.Equal("TransactionHash", prefix[0])},
+		Order:   []store.Order{store.Descending("TransactionOutIndex")},
+		Limit:   limit,
+		Offset:  offset,
+	}
 }
 
 // An Account model to store in Spanner.
@@ -367,8 +619,8 @@ type Account struct {
 }
 
 // Key for the Account.
-func (a Account) Key() spanner.Key {
-	return spanner.Key{a.Address}
+func (a Account) Key() store.Key {
+	return store.Key{a.Address}
 }
 
 // Table to store Accounts.
@@ -376,13 +628,9 @@ func (Account) Table() string {
 	return "Accounts"
 }
 
-// List statement for Accounts.
-func (Account) List(_ spanner.Key, limit, offset int64) spanner.Statement {
-	stmt := spanner.NewStatement("SELECT * FROM Accounts ORDER BY Address LIMIT @limit OFFSET @offset")
-	stmt.Params["limit"] = limit
-	stmt.Params["offset"] = offset
-
-	return stmt
+// List query for Accounts.
+func (Account) List(_ store.Key, limit, offset int64) store.Query {
+	return store.Query{Order: []store.Order{store.Ascending("Address")}, Limit: limit, Offset: offset}
 }
 
 // An AccountTransaction model to store in Spanner.
@@ -393,8 +641,8 @@ type AccountTransaction struct {
 }
 
 // Key for the AccountTransaction.
-func (a AccountTransaction) Key() spanner.Key {
-	return spanner.Key{a.Address, a.TransactionHash}
+func (a AccountTransaction) Key() store.Key {
+	return store.Key{a.Address, a.TransactionHash}
 }
 
 // Table to store AccountTransactions.
@@ -402,15 +650,14 @@ func (AccountTransaction) Table() string {
 	return "AccountTransactions"
 }
 
-// List statement for AccountTransactions.
-func (AccountTransaction) List(prefix spanner.Key, limit, offset int64) spanner.Statement {
-	stmt := spanner.NewStatement(
-		"SELECT * FROM AccountTransactions WHERE Address = @address ORDER BY TransactionHash LIMIT @limit OFFSET @offset")
-	stmt.Params["address"] = prefix[0]
-	stmt.Params["limit"] = limit
-	stmt.Params["offset"] = offset
-
-	return stmt
+// List query for AccountTransactions.
+func (AccountTransaction) List(prefix store.Key, limit, offset int64) store.Query {
+	return store.Query{
+		Filters: []store.Filter{store.Equal("Address", prefix[0])},
+		Order:   []store.Order{store.Ascending("TransactionHash")},
+		Limit:   limit,
+		Offset:  offset,
+	}
 }
 
 // An AccountStore model to store in Spanner.
@@ -423,8 +670,8 @@ type AccountStore struct {
 }
 
 // Key for the AccountStore.
-func (a AccountStore) Key() spanner.Key {
-	return spanner.Key{a.Address, a.Index}
+func (a AccountStore) Key() store.Key {
+	return store.Key{a.Address, a.Index}
 }
 
 // Table to store AccountStores.
@@ -432,15 +679,194 @@ func (AccountStore) Table() string {
 	return "AccountStores"
 }
 
-// List statement for AccountStores.
-func (AccountStore) List(prefix spanner.Key, limit, offset int64) spanner.Statement {
-	stmt := spanner.NewStatement(
-		"SELECT * FROM AccountStores WHERE Address = @address ORDER BY Index LIMIT @limit OFFSET @offset")
-	stmt.Params["address"] = prefix[0]
-	stmt.Params["limit"] = limit
-	stmt.Params["offset"] = offset
+// List query for AccountStores.
+func (AccountStore) List(prefix store.Key, limit, offset int64) store.Query {
+	return store.Query{
+		Filters: []store.Filter{store.Equal("Address", prefix[0])},
+		Order:   []store.Order{store.Ascending("Index")},
+		Limit:   limit,
+		Offset:  offset,
+	}
+}
+
+// An AccountOutput model to store in Spanner. Tracks every output owned by an address and whether it is spent.
+type AccountOutput struct {
+	Address             string
+	TransactionHash     string
+	TransactionOutIndex int64
+	Spent               *bool
+	ObserveTime         time.Time
+}
+
+// Key for the AccountOutput.
+func (a AccountOutput) Key() store.Key {
+	return store.Key{a.Address, a.TransactionHash, a.TransactionOutIndex}
+}
+
+// Table to store AccountOutputs.
+func (AccountOutput) Table() string {
+	return "AccountOutputs"
+}
+
+// List query for AccountOutputs. Only outputs that are still unspent are listed.
+func (AccountOutput) List(prefix store.Key, limit, offset int64) store.Query {
+	return store.Query{
+		Filters: []store.Filter{store.Equal("Address", prefix[0]), store.NotTrue("Spent")},
+		Order:   []store.Order{store.Ascending("TransactionHash"), store.Ascending("TransactionOutIndex")},
+		Limit:   limit,
+		Offset:  offset,
+	}
+}
+
+// A MissingTransaction model to store in Spanner. Tracks attempts to retrieve a transaction that couldn't be
+// fetched when its block was indexed.
+type MissingTransaction struct {
+	TransactionHash string
+	Height          int64
+	Attempts        int64
+	LastError       string
+	Abandoned       *bool
+	Resolved        *bool
+	ObserveTime     time.Time
+}
+
+// Key for the MissingTransaction.
+func (m MissingTransaction) Key() store.Key {
+	return store.Key{m.TransactionHash}
+}
+
+// Table to store MissingTransactions.
+func (MissingTransaction) Table() string {
+	return "MissingTransactions"
+}
+
+// List query for MissingTransactions. Only those still being retried are listed, least attempted first.
+func (MissingTransaction) List(_ store.Key, limit, offset int64) store.Query {
+	return store.Query{
+		Filters: []store.Filter{store.NotTrue("Abandoned"), store.NotTrue("Resolved")},
+		Order: []store.Order{
+			store.Ascending("Attempts"), store.Ascending("Height"), store.Ascending("TransactionHash"),
+		},
+		Limit:  limit,
+		Offset: offset,
+	}
+}
+
+// An UnresolvedSpend model to store in Spanner. Records an input that consumed an output which hasn't been
+// indexed, so the output can be marked spent if its transaction is recovered later.
+type UnresolvedSpend struct {
+	ConsumedTransactionHash  string
+	ConsumedTransactionIndex int64
+	TransactionHash          string
+	TransactionIndex         int64
+	Height                   int64
+	ObserveTime              time.Time
+}
+
+// Key for the UnresolvedSpend.
+func (u UnresolvedSpend) Key() store.Key {
+	return store.Key{u.ConsumedTransactionHash, u.ConsumedTransactionIndex}
+}
+
+// Table to store UnresolvedSpends.
+func (UnresolvedSpend) Table() string {
+	return "UnresolvedSpends"
+}
+
+// List query for UnresolvedSpends.
+func (UnresolvedSpend) List(prefix store.Key, limit, offset int64) store.Query {
+	return store.Query{
+		Filters: []store.Filter{store.Equal("ConsumedTransactionHash", prefix[0])},
+		Order:   []store.Order{store.Ascending("ConsumedTransactionIndex")},
+		Limit:   limit,
+		Offset:  offset,
+	}
+}
 
-	return stmt
+// A Checkpoint model to store in Spanner. Records the highest block height fully committed by an indexer.
+type Checkpoint struct {
+	Name        string
+	Height      int64
+	ObserveTime time.Time
+}
+
+// Key for the Checkpoint.
+func (c Checkpoint) Key() store.Key {
+	return store.Key{c.Name}
+}
+
+// Table to store Checkpoints.
+func (Checkpoint) Table() string {
+	return "Checkpoints"
+}
+
+// List query for Checkpoints.
+func (Checkpoint) List(_ store.Key, limit, offset int64) store.Query {
+	return store.Query{Order: []store.Order{store.Ascending("Name")}, Limit: limit, Offset: offset}
+}
+
+// A Webhook registered to receive indexer events over HTTP, signed with its secret. Only its owner can manage it.
+type Webhook struct {
+	ID         string
+	Owner      string
+	URL        string
+	Secret     string
+	Blocks     *bool
+	Addresses  []string
+	CreateTime time.Time
+}
+
+// Key for the Webhook.
+func (w Webhook) Key() store.Key {
+	return store.Key{w.ID}
+}
+
+// Table to store Webhooks.
+func (Webhook) Table() string {
+	return "Webhooks"
+}
+
+// List query for Webhooks. Given an owner as the prefix, lists only the webhooks they own.
+func (Webhook) List(prefix store.Key, limit, offset int64) store.Query {
+	q := store.Query{Order: []store.Order{store.Ascending("ID")}, Limit: limit, Offset: offset}
+	if len(prefix) > 0 {
+		q.Filters = []store.Filter{store.Equal("Owner", prefix[0])}
+	}
+
+	return q
+}
+
+// A WebhookDeadLetter records an event that couldn't be delivered to a Webhook, queued to be retried with backoff
+// from its ObserveTime until it is abandoned.
+type WebhookDeadLetter struct {
+	ID          string
+	DeliveryID  string
+	Event       string
+	Payload     string
+	Attempts    int64
+	LastError   string
+	Abandoned   *bool
+	ObserveTime time.Time
+}
+
+// Key for the WebhookDeadLetter.
+func (w WebhookDeadLetter) Key() store.Key {
+	return store.Key{w.ID, w.DeliveryID}
+}
+
+// Table to store WebhookDeadLetters.
+func (WebhookDeadLetter) Table() string {
+	return "WebhookDeadLetters"
+}
+
+// List query for WebhookDeadLetters of a Webhook.
+func (WebhookDeadLetter) List(prefix store.Key, limit, offset int64) store.Query {
+	return store.Query{
+		Filters: []store.Filter{store.Equal("ID", prefix[0])},
+		Order:   []store.Order{store.Ascending("DeliveryID")},
+		Limit:   limit,
+		Offset:  offset,
+	}
 }
 
 // Stores is a collection of all alicenet Storable objects.
@@ -453,18 +879,137 @@ type Stores struct {
 	Accounts            store.Store[Account]
 	AccountTransactions store.Store[AccountTransaction]
 	AccountStores       store.Store[AccountStore]
+	AccountOutputs      store.Store[AccountOutput]
+	MissingTransactions store.Store[MissingTransaction]
+	UnresolvedSpends    store.Store[UnresolvedSpend]
+	Checkpoints         store.Store[Checkpoint]
+	Webhooks            store.Store[Webhook]
+	WebhookDeadLetters  store.Store[WebhookDeadLetter]
+
+	transactor store.Transactor
+}
+
+// InTransaction runs fn so that every write it makes through the Stores is committed atomically.
+func (s *Stores) InTransaction(ctx context.Context, fn func(context.Context) error) error {
+	if err := s.transactor.InTransaction(ctx, fn); err != nil {
+		return fmt.Errorf("stores: %w", err)
+	}
+
+	return nil
+}
+
+// Cache reads from the named tables. Only tables whose rows don't change once written can be cached. Transactions are
+// filled in when reconciled, outputs are marked when spent, and the rest are updated as blocks are indexed.
+func (s *Stores) Cache(opts store.CacheOptions, tables ...string) error {
+	for _, table := range tables {
+		switch table {
+		case Block{}.Table():
+			s.Blocks = store.Cache(s.Blocks, opts)
+		case TransactionInput{}.Table():
+			s.TransactionInputs = store.Cache(s.TransactionInputs, opts)
+		case AccountTransaction{}.Table():
+			s.AccountTransactions = store.Cache(s.AccountTransactions, opts)
+		case Transaction{}.Table(), DataStore{}.Table(), ValueStore{}.Table(), Account{}.Table(),
+			AccountStore{}.Table(), AccountOutput{}.Table(), MissingTransaction{}.Table(), UnresolvedSpend{}.Table(),
+			Checkpoint{}.Table(), Webhook{}.Table(), WebhookDeadLetter{}.Table():
+			return fmt.Errorf("%w: %s", ErrUncachedTable, table)
+		default:
+			return fmt.Errorf("%w: %s", ErrUnknownTable, table)
+		}
+	}
+
+	return nil
 }
 
 // InSpanner storage of all alicenet resources.
-func InSpanner(client *spanner.Client) *Stores {
+func InSpanner(client *spanner.Client, opts ...store.SpannerOption) *Stores {
+	return &Stores{
+		Blocks:              store.InSpanner[Block](client, opts...),
+		Transactions:        store.InSpanner[Transaction](client, opts...),
+		TransactionInputs:   store.InSpanner[TransactionInput](client, opts...),
+		DataStores:          store.InSpanner[DataStore](client, opts...),
+		ValueStores:         store.InSpanner[ValueStore](client, opts...),
+		Accounts:            store.InSpanner[Account](client, opts...),
+		AccountTransactions: store.InSpanner[AccountTransaction](client, opts...),
+		AccountStores:       store.InSpanner[AccountStore](client, opts...),
+		AccountOutputs:      store.InSpanner[AccountOutput](client, opts...),
+		MissingTransactions: store.InSpanner[MissingTransaction](client, opts...),
+		UnresolvedSpends:    store.InSpanner[UnresolvedSpend](client, opts...),
+		Checkpoints:         store.InSpanner[Checkpoint](client, opts...),
+		Webhooks:            store.InSpanner[Webhook](client, opts...),
+		WebhookDeadLetters:  store.InSpanner[WebhookDeadLetter](client, opts...),
+		transactor:          store.TransactInSpanner(client),
+	}
+}
+
+// InPostgres storage of all alicenet resources.
+func InPostgres(db *sql.DB) *Stores {
+	return &Stores{
+		Blocks:              store.InPostgres[Block](db),
+		Transactions:        store.InPostgres[Transaction](db),
+		TransactionInputs:   store.InPostgres[TransactionInput](db),
+		DataStores:          store.InPostgres[DataStore](db),
+		ValueStores:         store.InPostgres[ValueStore](db),
+		Accounts:            store.InPostgres[Account](db),
+		AccountTransactions: store.InPostgres[AccountTransaction](db),
+		AccountStores:       store.InPostgres[AccountStore](db),
+		AccountOutputs:      store.InPostgres[AccountOutput](db),
+		MissingTransactions: store.InPostgres[MissingTransaction](db),
+		UnresolvedSpends:    store.InPostgres[UnresolvedSpend](db),
+		Checkpoints:         store.InPostgres[Checkpoint](db),
+		Webhooks:            store.InPostgres[Webhook](db),
+		WebhookDeadLetters:  store.InPostgres[WebhookDeadLetter](db),
+		transactor:          store.TransactInPostgres(db),
+	}
+}
+
+// InSQLite storage of all alicenet resources.
+func InSQLite(db *sql.DB) *Stores {
+	return &Stores{
+		Blocks:              store.InSQLite[Block](db),
+		Transactions:        store.InSQLite[Transaction](db),
+		TransactionInputs:   store.InSQLite[TransactionInput](db),
+		DataStores:          store.InSQLite[DataStore](db),
+		ValueStores:         store.InSQLite[ValueStore](db),
+		Accounts:            store.InSQLite[Account](db),
+		AccountTransactions: store.InSQLite[AccountTransaction](db),
+		AccountStores:       store.InSQLite[AccountStore](db),
+		AccountOutputs:      store.InSQLite[AccountOutput](db),
+		MissingTransactions: store.InSQLite[MissingTransaction](db),
+		UnresolvedSpends:    store.InSQLite[UnresolvedSpend](db),
+		Checkpoints:         store.InSQLite[Checkpoint](db),
+		Webhooks:            store.InSQLite[Webhook](db),
+		WebhookDeadLetters:  store.InSQLite[WebhookDeadLetter](db),
+		transactor:          store.TransactInSQLite(db),
+	}
+}
+
+// InMemory storage of all alicenet resources, for tests and local development. Stores are interleaved as in the
+// Spanner schema, so deletes cascade the same way.
+func InMemory() *Stores {
+	transactionInputs := store.InMemory[TransactionInput]()
+	dataStores := store.InMemory[DataStore]()
+	valueStores := store.InMemory[ValueStore]()
+	accountTransactions := store.InMemory[AccountTransaction]()
+	accountStores := store.InMemory[AccountStore]()
+	accountOutputs := store.InMemory[AccountOutput]()
+	webhookDeadLetters := store.InMemory[WebhookDeadLetter]()
+
 	return &Stores{
-		Blocks:              store.InSpanner[Block](client),
-		Transactions:        store.InSpanner[Transaction](client),
-		TransactionInputs:   store.InSpanner[TransactionInput](client),
-		DataStores:          store.InSpanner[DataStore](client),
-		ValueStores:         store.InSpanner[ValueStore](client),
-		Accounts:            store.InSpanner[Account](client),
-		AccountTransactions: store.InSpanner[AccountTransaction](client),
-		AccountStores:       store.InSpanner[AccountStore](client),
+		Blocks:              store.InMemory[Block](),
+		Transactions:        store.InMemory[Transaction]().Interleave(transactionInputs, dataStores, valueStores),
+		TransactionInputs:   transactionInputs,
+		DataStores:          dataStores,
+		ValueStores:         valueStores,
+		Accounts:            store.InMemory[Account]().Interleave(accountTransactions, accountStores, accountOutputs),
+		AccountTransactions: accountTransactions,
+		AccountStores:       accountStores,
+		AccountOutputs:      accountOutputs,
+		MissingTransactions: store.InMemory[MissingTransaction](),
+		UnresolvedSpends:    store.InMemory[UnresolvedSpend](),
+		Checkpoints:         store.InMemory[Checkpoint](),
+		Webhooks:            store.InMemory[Webhook]().Interleave(webhookDeadLetters),
+		WebhookDeadLetters:  webhookDeadLetters,
+		transactor:          store.TransactInMemory(),
 	}
 }
@@ -2,8 +2,13 @@ package alicenet
 
 import (
 	"context"
+	"errors"
 	"flag"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+
+	"github.com/alicenet/utilities/internal/store"
 )
 
 const addr = "api.alicenet.duckdns.org"
@@ -18,7 +23,11 @@ func TestBlockHeight(t *testing.T) {
 
 	t.Parallel()
 
-	anet := Connect(addr)
+	anet, err := Connect(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	ctx := context.Background()
 
 	height, err := anet.Height(ctx)
@@ -40,7 +49,11 @@ func TestBlockHeader(t *testing.T) {
 
 	t.Parallel()
 
-	anet := Connect(addr)
+	anet, err := Connect(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	ctx := context.Background()
 
 	header, err := anet.BlockHeader(ctx, 200000)
@@ -69,7 +82,11 @@ func TestTransaction(t *testing.T) {
 
 	t.Parallel()
 
-	anet := Connect(addr)
+	anet, err := Connect(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	ctx := context.Background()
 
 	txn, err := anet.Transaction(ctx, "7633e92ab233af14e63517b0df66551303d49de2b06a67833ee67f01ae9ffa00")
@@ -87,3 +104,123 @@ func TestTransaction(t *testing.T) {
 
 	t.Logf("transaction: %+v", txn)
 }
+
+func TestTransactionOutputs(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"Tx": {
+			"Vin": [{"TXInLinker": {"TxHash": "abc", "TXInPreImage": {"ConsumedTxHash": "def", "ConsumedTxIdx": 2}}}],
+			"Vout": [
+				{"ValueStore": {"TxHash": "abc", "VSPreImage": {"Owner": "owner", "Value": "ff"}}},
+				{"AtomicSwap": {}},
+				{"DataStore": {"DSLinker": {"TxHash": "abc", "DSPreImage": {"Index": "01", "TXOutIdx": 2}}}}
+			]
+		}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	anet, err := Connect(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txn, err := anet.Transaction(context.Background(), "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if vin := txn.GetTx().GetVin(); len(vin) != 1 || vin[0].GetTXInLinker().GetTXInPreImage().GetConsumedTxIdx() != 2 {
+		t.Errorf("inputs not converted: %v", vin)
+	}
+
+	vout := txn.GetTx().GetVout()
+	if len(vout) != 3 {
+		t.Fatalf("outputs, want: %d, got: %d", 3, len(vout))
+	}
+
+	if vs := vout[0].GetValueStore(); vs.GetVSPreImage().GetValue() != "ff" || vs.GetTxHash() != "abc" {
+		t.Errorf("value store not converted: %v", vout[0])
+	}
+
+	// Outputs the shim can't decode keep their place, so later output indexes stay aligned.
+	if vout[1].GetUtxo() != nil {
+		t.Errorf("atomic swap, want: no stores, got: %v", vout[1])
+	}
+
+	if ds := vout[2].GetDataStore(); ds.GetDSLinker().GetDSPreImage().GetTXOutIdx() != 2 {
+		t.Errorf("data store not converted: %v", vout[2])
+	}
+}
+
+func TestConnectOptions(t *testing.T) {
+	t.Parallel()
+
+	want := map[string]string{
+		"Authorization": "Bearer token",
+		"User-Agent":    "indexer/test",
+		"X-Api-Key":     "key",
+		"Content-Type":  "application/json",
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/prefix/"+blockNumberPath {
+			t.Errorf("path, want: %s, got: %s", "/prefix/"+blockNumberPath, r.URL.Path)
+		}
+
+		for k, v := range want {
+			if got := r.Header.Get(k); got != v {
+				t.Errorf("header %s, want: %s, got: %s", k, v, got)
+			}
+		}
+
+		_, _ = w.Write([]byte(`{"BlockHeight": 7}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	anet, err := Connect(
+		srv.URL+"/prefix",
+		WithBearerToken("token"),
+		WithUserAgent("indexer/test"),
+		WithHeader("X-Api-Key", "key"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	height, err := anet.Height(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if height != 7 {
+		t.Errorf("want: %d, got: %d", 7, height)
+	}
+}
+
+func TestConnectInvalidURL(t *testing.T) {
+	t.Parallel()
+
+	for _, raw := range []string{"ftp://example.com", "https://", "http://[::1"} {
+		if _, err := Connect(raw); !errors.Is(err, ErrInvalidURL) {
+			t.Errorf("%s, want: %v, got: %v", raw, ErrInvalidURL, err)
+		}
+	}
+}
+
+func TestStoresCache(t *testing.T) {
+	t.Parallel()
+
+	for table, want := range map[string]error{
+		"Blocks":       nil,
+		"Transactions": ErrUncachedTable,
+		"ValueStores":  ErrUncachedTable,
+		"Accounts":     ErrUncachedTable,
+		"Checkpoints":  ErrUncachedTable,
+		"Unknown":      ErrUnknownTable,
+	} {
+		if err := InMemory().Cache(store.CacheOptions{}, table); !errors.Is(err, want) {
+			t.Errorf("cache %s, want: %v, got: %v", table, want, err)
+		}
+	}
+}
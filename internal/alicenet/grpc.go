@@ -0,0 +1,171 @@
+package alicenet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opencensus.io/plugin/ocgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/alicenet/alicenet/proto"
+)
+
+// GRPCClient to interact with alicenet through the native LocalState GRPC service.
+type GRPCClient struct {
+	conn   *grpc.ClientConn
+	client proto.LocalStateClient
+}
+
+// Ensure GRPCClient matches the package Interface.
+var _ Interface = &GRPCClient{}
+
+// DialGRPC connects to the alicenet LocalState GRPC service at target. The options must include transport
+// credentials.
+//
+// Interceptors added by the WithGRPC options run in the order they are given. WithGRPCRetries must come before
+// WithGRPCCircuitBreaker and WithGRPCRequestTimeout, so the breaker counts and the timeout bounds each attempt rather
+// than the call as a whole.
+func DialGRPC(target string, opts ...grpc.DialOption) (*GRPCClient, error) {
+	opts = append(opts, grpc.WithStatsHandler(&ocgrpc.ClientHandler{}))
+
+	conn, err := grpc.Dial(target, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("dial: %w", err)
+	}
+
+	return &GRPCClient{conn: conn, client: proto.NewLocalStateClient(conn)}, nil
+}
+
+// WithGRPCMetadata sends the given key/value pairs as metadata on every call, the GRPC equivalent of WithHeader.
+func WithGRPCMetadata(kv ...string) grpc.DialOption {
+	return grpc.WithChainUnaryInterceptor(func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		return invoker(metadata.AppendToOutgoingContext(ctx, kv...), method, req, reply, cc, opts...)
+	})
+}
+
+// WithGRPCRetries of transient errors, the GRPC equivalent of WithRetries. Interceptors chained after it, such as
+// WithGRPCCircuitBreaker and WithGRPCRequestTimeout, apply to each attempt.
+func WithGRPCRetries(attempts int, initial, max time.Duration) grpc.DialOption {
+	policy := retryPolicy{attempts: attempts, initial: initial, max: max}
+
+	return grpc.WithChainUnaryInterceptor(func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		for attempt := 1; ; attempt++ {
+			err := invoker(ctx, method, req, reply, cc, opts...)
+
+			// An open breaker fails fast rather than waiting out its cooldown.
+			if err == nil || !Transient(err) || errors.Is(err, ErrCircuitOpen) ||
+				attempt >= policy.attempts || ctx.Err() != nil {
+				return err
+			}
+
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("retry: %w", ctx.Err())
+			case <-time.After(policy.backoff(attempt)):
+			}
+		}
+	})
+}
+
+// WithGRPCCircuitBreaker stops sending requests for cooldown after threshold consecutive transient errors, the GRPC
+// equivalent of WithCircuitBreaker. Given after WithGRPCRetries, so each failed attempt counts towards the threshold.
+func WithGRPCCircuitBreaker(threshold int, cooldown time.Duration) grpc.DialOption {
+	b := &breaker{threshold: threshold, cooldown: cooldown}
+
+	return grpc.WithChainUnaryInterceptor(func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		if err := b.allow(); err != nil {
+			return err
+		}
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+
+		b.record(ctx, err)
+
+		return err
+	})
+}
+
+// WithGRPCRequestTimeout bounds how long each individual attempt may take, the GRPC equivalent of
+// WithRequestTimeout. Given after WithGRPCRetries, otherwise it bounds all attempts together.
+func WithGRPCRequestTimeout(timeout time.Duration) grpc.DialOption {
+	return grpc.WithChainUnaryInterceptor(func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		if timeout > 0 {
+			var cancel context.CancelFunc
+
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		return invoker(ctx, method, req, reply, cc, opts...)
+	})
+}
+
+// Close the underlying connection.
+func (g *GRPCClient) Close() error {
+	if err := g.conn.Close(); err != nil {
+		return fmt.Errorf("close: %w", err)
+	}
+
+	return nil
+}
+
+// Height of chain.
+func (g *GRPCClient) Height(ctx context.Context) (uint32, error) {
+	resp, err := g.client.GetBlockNumber(ctx, &proto.BlockNumberRequest{})
+	if err != nil {
+		return 0, fmt.Errorf("height: %w", err)
+	}
+
+	return resp.BlockHeight, nil
+}
+
+// BlockHeader at a given height.
+func (g *GRPCClient) BlockHeader(ctx context.Context, height uint32) (*proto.BlockHeader, error) {
+	resp, err := g.client.GetBlockHeader(ctx, &proto.BlockHeaderRequest{Height: height})
+	if err != nil {
+		return nil, fmt.Errorf("header: %w", err)
+	}
+
+	return resp.BlockHeader, nil
+}
+
+// Transaction for a given hash.
+func (g *GRPCClient) Transaction(ctx context.Context, hash string) (*proto.MinedTransactionResponse, error) {
+	resp, err := g.client.GetMinedTransaction(ctx, &proto.MinedTransactionRequest{TxHash: hash})
+	if err != nil {
+		return nil, fmt.Errorf("transaction: %w", err)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,235 @@
+package alicenet
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/alicenet/alicenet/proto"
+)
+
+const bufSize = 1024 * 1024
+
+// localState serves canned responses for the LocalState GRPC service.
+type localState struct {
+	proto.UnimplementedLocalStateServer
+}
+
+func (localState) GetBlockNumber(context.Context, *proto.BlockNumberRequest) (*proto.BlockNumberResponse, error) {
+	return &proto.BlockNumberResponse{BlockHeight: 42}, nil
+}
+
+func (localState) GetMinedTransaction(
+	_ context.Context, req *proto.MinedTransactionRequest,
+) (*proto.MinedTransactionResponse, error) {
+	if req.TxHash != "abc" {
+		return nil, status.Error(codes.NotFound, "unknown transaction")
+	}
+
+	return &proto.MinedTransactionResponse{
+		Tx: &proto.Tx{
+			Vin: []*proto.TXIn{{
+				TXInLinker: &proto.TXInLinker{
+					TXInPreImage: &proto.TXInPreImage{ChainID: 1, ConsumedTxIdx: 2, ConsumedTxHash: "def"},
+					TxHash:       "abc",
+				},
+				Signature: "sig",
+			}},
+			Vout: []*proto.TXOut{
+				{Utxo: &proto.TXOut_ValueStore{ValueStore: &proto.ValueStore{
+					VSPreImage: &proto.VSPreImage{ChainID: 1, Value: "ff", TXOutIdx: 0, Owner: "owner", Fee: "1"},
+					TxHash:     "abc",
+				}}},
+				{Utxo: &proto.TXOut_DataStore{DataStore: &proto.DataStore{
+					DSLinker: &proto.DSLinker{
+						DSPreImage: &proto.DSPreImage{ChainID: 1, Index: "01", IssuedAt: 3, TXOutIdx: 1, Owner: "owner"},
+						TxHash:     "abc",
+					},
+					Signature: "dssig",
+				}}},
+			},
+		},
+	}, nil
+}
+
+// unavailableState fails every call as unavailable, counting them, or blocks until the call is cancelled if slow.
+type unavailableState struct {
+	proto.UnimplementedLocalStateServer
+	calls *atomic.Int32
+	slow  bool
+}
+
+func (u unavailableState) GetBlockNumber(ctx context.Context, _ *proto.BlockNumberRequest) (
+	*proto.BlockNumberResponse, error,
+) {
+	u.calls.Add(1)
+
+	if u.slow {
+		<-ctx.Done()
+	}
+
+	return nil, status.Error(codes.Unavailable, "unavailable")
+}
+
+// dialTest connects a GRPCClient to an in memory LocalState server.
+func dialTest(t *testing.T, opts ...grpc.DialOption) *GRPCClient {
+	t.Helper()
+
+	return dialServer(t, localState{}, opts...)
+}
+
+// dialServer connects a GRPCClient to an in memory server.
+func dialServer(t *testing.T, server proto.LocalStateServer, opts ...grpc.DialOption) *GRPCClient {
+	t.Helper()
+
+	lis := bufconn.Listen(bufSize)
+	srv := grpc.NewServer()
+	proto.RegisterLocalStateServer(srv, server)
+
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+
+	t.Cleanup(srv.Stop)
+
+	opts = append(
+		opts,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+	)
+
+	client, err := DialGRPC("bufnet", opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	return client
+}
+
+func TestGRPCHeight(t *testing.T) {
+	t.Parallel()
+
+	height, err := dialTest(t).Height(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if height != 42 {
+		t.Errorf("want: %d, got: %d", 42, height)
+	}
+}
+
+func TestGRPCTransaction(t *testing.T) {
+	t.Parallel()
+
+	txn, err := dialTest(t).Transaction(context.Background(), "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if vin := txn.GetTx().GetVin(); len(vin) != 1 || vin[0].GetTXInLinker().GetTXInPreImage().GetConsumedTxIdx() != 2 {
+		t.Errorf("inputs, want: one consuming index 2, got: %v", vin)
+	}
+
+	vout := txn.GetTx().GetVout()
+	if len(vout) != 2 {
+		t.Fatalf("outputs, want: %d, got: %d", 2, len(vout))
+	}
+
+	if vs := vout[0].GetValueStore(); vs.GetVSPreImage().GetValue() != "ff" || vs.GetTxHash() != "abc" {
+		t.Errorf("value store, want: ff from abc, got: %v", vout[0])
+	}
+
+	if ds := vout[1].GetDataStore(); ds.GetDSLinker().GetDSPreImage().GetIssuedAt() != 3 || ds.GetSignature() != "dssig" {
+		t.Errorf("data store, want: issued at 3 signed dssig, got: %v", vout[1])
+	}
+}
+
+func TestGRPCTransactionNotFound(t *testing.T) {
+	t.Parallel()
+
+	_, err := dialTest(t).Transaction(context.Background(), "missing")
+	if !NotFound(err) {
+		t.Errorf("expected not found, got: %v", err)
+	}
+
+	if Transient(err) {
+		t.Errorf("not found should be permanent: %v", err)
+	}
+}
+
+func TestGRPCRetries(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	client := dialServer(t, unavailableState{calls: &calls}, WithGRPCRetries(3, time.Millisecond, time.Millisecond))
+
+	if _, err := client.Height(context.Background()); !Transient(err) {
+		t.Errorf("expected transient error, got: %v", err)
+	}
+
+	if got := calls.Load(); got != 3 {
+		t.Errorf("calls, want: %d, got: %d", 3, got)
+	}
+}
+
+func TestGRPCCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	client := dialServer(
+		t,
+		unavailableState{calls: &calls},
+		WithGRPCRetries(3, time.Millisecond, time.Millisecond),
+		WithGRPCCircuitBreaker(2, time.Hour),
+	)
+
+	// The breaker trips after the second attempt, failing the third without reaching the server.
+	if _, err := client.Height(context.Background()); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("want: %v, got: %v", ErrCircuitOpen, err)
+	}
+
+	if got := calls.Load(); got != 2 {
+		t.Errorf("calls, want: %d, got: %d", 2, got)
+	}
+}
+
+func TestGRPCRequestTimeout(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	client := dialServer(
+		t,
+		unavailableState{calls: &calls, slow: true},
+		WithGRPCRetries(2, time.Millisecond, time.Millisecond),
+		WithGRPCRequestTimeout(10*time.Millisecond),
+	)
+
+	// Each attempt times out on its own, so the call is retried.
+	_, err := client.Height(context.Background())
+	if st, ok := grpcStatus(err); !ok || st.Code() != codes.DeadlineExceeded {
+		t.Errorf("want: %v, got: %v", codes.DeadlineExceeded, err)
+	}
+
+	if got := calls.Load(); got != 2 {
+		t.Errorf("calls, want: %d, got: %d", 2, got)
+	}
+}
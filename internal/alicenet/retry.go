@@ -0,0 +1,150 @@
+package alicenet
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrCircuitOpen is returned without contacting alicenet while the node is considered unhealthy.
+var ErrCircuitOpen = errors.New("circuit open: alicenet unhealthy")
+
+// grpcStatus of an error returned by a GRPC client, if it has one.
+func grpcStatus(err error) (*status.Status, bool) {
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &grpcErr) {
+		return nil, false
+	}
+
+	return grpcErr.GRPCStatus(), true
+}
+
+// Transient reports whether an error from alicenet is likely to succeed if retried. Server errors, rate limiting,
+// timeouts and transport failures are transient, while other API errors such as not found are permanent.
+func Transient(err error) bool {
+	var apiErr APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError || apiErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	if st, ok := grpcStatus(err); ok {
+		//nolint:exhaustive // Every other code is permanent.
+		switch st.Code() {
+		case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+			return true
+		default:
+			return false
+		}
+	}
+
+	if errors.Is(err, ErrCircuitOpen) {
+		return true
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr)
+}
+
+// NotFound reports whether alicenet did not have the requested resource.
+func NotFound(err error) bool {
+	if st, ok := grpcStatus(err); ok {
+		return st.Code() == codes.NotFound
+	}
+
+	var apiErr APIError
+
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// A retryPolicy for transient errors.
+type retryPolicy struct {
+	attempts int
+	initial  time.Duration
+	max      time.Duration
+}
+
+// backoff to wait after a failed attempt. Uses full jitter so that many clients don't retry in lockstep.
+func (r retryPolicy) backoff(attempt int) time.Duration {
+	ceiling := r.initial << (attempt - 1)
+	if ceiling > r.max || ceiling <= 0 {
+		ceiling = r.max
+	}
+
+	if ceiling <= 0 {
+		return 0
+	}
+
+	//nolint:gosec // Jitter doesn't need a secure source of randomness.
+	return time.Duration(rand.Int63n(int64(ceiling)))
+}
+
+// A breaker trips after too many consecutive transient errors, failing fast until the cooldown has passed. Once it
+// has, a single request is let through as a probe while the rest keep failing fast. The breaker closes if the probe
+// succeeds, and opens for another cooldown if it fails.
+type breaker struct {
+	sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// allow a request to be made unless the breaker is open, or half open with a probe already in flight.
+func (b *breaker) allow() error {
+	if b.threshold <= 0 {
+		return nil
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	if b.failures < b.threshold {
+		return nil
+	}
+
+	if b.probing || time.Now().Before(b.openUntil) {
+		return ErrCircuitOpen
+	}
+
+	b.probing = true
+
+	return nil
+}
+
+// record the outcome of a request. Giving up because the caller cancelled says nothing about the health of alicenet,
+// so only lets another request probe it.
+func (b *breaker) record(ctx context.Context, err error) {
+	if b.threshold <= 0 {
+		return
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	b.probing = false
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	if err == nil || !Transient(err) {
+		b.failures = 0
+
+		return
+	}
+
+	b.failures++
+
+	if b.failures >= b.threshold {
+		b.openUntil = time.Now().Add(b.cooldown)
+	}
+}
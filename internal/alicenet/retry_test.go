@@ -0,0 +1,198 @@
+package alicenet
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// connectTest server with the client trusting its certificate.
+func connectTest(t *testing.T, handler http.HandlerFunc, opts ...Option) *Client {
+	t.Helper()
+
+	srv := httptest.NewTLSServer(handler)
+	t.Cleanup(srv.Close)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+
+	c, err := Connect(strings.TrimPrefix(srv.URL, "https://"), append(opts, WithRootCAs(pool))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func TestRetryTransient(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	c := connectTest(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"BlockHeight": 42}`))
+	}, WithRetries(3, time.Millisecond, time.Millisecond))
+
+	height, err := c.Height(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if height != 42 {
+		t.Errorf("want: %d, got: %d", 42, height)
+	}
+
+	if calls.Load() != 3 {
+		t.Errorf("attempts, want: %d, got: %d", 3, calls.Load())
+	}
+}
+
+func TestNoRetryPermanent(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	c := connectTest(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}, WithRetries(3, time.Millisecond, time.Millisecond))
+
+	_, err := c.Transaction(context.Background(), "abc")
+	if !NotFound(err) {
+		t.Errorf("expected not found, got: %v", err)
+	}
+
+	if Transient(err) {
+		t.Errorf("not found should be permanent: %v", err)
+	}
+
+	if calls.Load() != 1 {
+		t.Errorf("attempts, want: %d, got: %d", 1, calls.Load())
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	t.Parallel()
+
+	c := connectTest(t, func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}, WithRequestTimeout(time.Millisecond))
+
+	_, err := c.Height(context.Background())
+	if !Transient(err) {
+		t.Errorf("timeout should be transient, got: %v", err)
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	c := connectTest(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}, WithCircuitBreaker(2, time.Hour))
+
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.Height(ctx); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("attempt %d, expected server error, got: %v", i, err)
+		}
+	}
+
+	if _, err := c.Height(ctx); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("want: %v, got: %v", ErrCircuitOpen, err)
+	}
+
+	if calls.Load() != 2 {
+		t.Errorf("requests, want: %d, got: %d", 2, calls.Load())
+	}
+}
+
+func TestCircuitBreakerProbe(t *testing.T) {
+	t.Parallel()
+
+	const cooldown = 50 * time.Millisecond
+
+	var calls atomic.Int32
+
+	var healthy atomic.Bool
+
+	probing, release := make(chan struct{}), make(chan struct{})
+
+	c := connectTest(t, func(w http.ResponseWriter, r *http.Request) {
+		// The first probe is held until the test has checked other requests fail fast meanwhile.
+		if calls.Add(1) == 2 {
+			close(probing)
+			<-release
+		}
+
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"BlockHeight": 7}`))
+	}, WithCircuitBreaker(1, cooldown))
+
+	ctx := context.Background()
+
+	if _, err := c.Height(ctx); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected server error, got: %v", err)
+	}
+
+	time.Sleep(2 * cooldown)
+
+	probed := make(chan error)
+
+	go func() {
+		_, err := c.Height(ctx)
+		probed <- err
+	}()
+
+	<-probing
+
+	if _, err := c.Height(ctx); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("during probe, want: %v, got: %v", ErrCircuitOpen, err)
+	}
+
+	close(release)
+
+	if err := <-probed; err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("probe, expected server error, got: %v", err)
+	}
+
+	// A failed probe opens the breaker for another cooldown.
+	if _, err := c.Height(ctx); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("after failed probe, want: %v, got: %v", ErrCircuitOpen, err)
+	}
+
+	healthy.Store(true)
+	time.Sleep(2 * cooldown)
+
+	// A successful probe closes it again.
+	for i := 0; i < 2; i++ {
+		if _, err := c.Height(ctx); err != nil {
+			t.Errorf("attempt %d after successful probe, want: no error, got: %v", i, err)
+		}
+	}
+
+	if calls.Load() != 4 {
+		t.Errorf("requests, want: %d, got: %d", 4, calls.Load())
+	}
+}
@@ -0,0 +1,5 @@
+CREATE TABLE Checkpoints (
+    Name        STRING(MAX) NOT NULL,
+    Height      INT64 NOT NULL,
+    ObserveTime TIMESTAMP NOT NULL OPTIONS (allow_commit_timestamp=true),
+) PRIMARY KEY (Name);
@@ -0,0 +1,3 @@
+ALTER TABLE ValueStores DROP COLUMN Spent;
+
+ALTER TABLE DataStores DROP COLUMN Spent;
@@ -0,0 +1,7 @@
+-- Spanner migrations can only change the schema, so Spent are left unset for outputs indexed before this
+-- migration. ListUnspentOutputs is only correct for databases indexed from scratch after it, unlike Postgres and
+-- SQLite, which backfill them.
+
+ALTER TABLE ValueStores ADD COLUMN Spent BOOL;
+
+ALTER TABLE DataStores ADD COLUMN Spent BOOL;
@@ -0,0 +1,13 @@
+DROP TABLE AccountOutputs;
+
+ALTER TABLE DataStores DROP COLUMN SpentHeight;
+
+ALTER TABLE DataStores DROP COLUMN SpentInputIndex;
+
+ALTER TABLE DataStores DROP COLUMN SpentTransactionHash;
+
+ALTER TABLE ValueStores DROP COLUMN SpentHeight;
+
+ALTER TABLE ValueStores DROP COLUMN SpentInputIndex;
+
+ALTER TABLE ValueStores DROP COLUMN SpentTransactionHash;
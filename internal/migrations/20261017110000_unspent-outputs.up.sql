@@ -0,0 +1,24 @@
+-- Spanner migrations can only change the schema, so outputs indexed before this migration have no spending input
+-- and no AccountOutputs rows. ListUnspentOutputs is only correct for databases indexed from scratch after it, unlike
+-- Postgres and SQLite, which backfill them.
+
+ALTER TABLE ValueStores ADD COLUMN SpentTransactionHash STRING(MAX);
+
+ALTER TABLE ValueStores ADD COLUMN SpentInputIndex INT64;
+
+ALTER TABLE ValueStores ADD COLUMN SpentHeight INT64;
+
+ALTER TABLE DataStores ADD COLUMN SpentTransactionHash STRING(MAX);
+
+ALTER TABLE DataStores ADD COLUMN SpentInputIndex INT64;
+
+ALTER TABLE DataStores ADD COLUMN SpentHeight INT64;
+
+CREATE TABLE AccountOutputs (
+    Address             STRING(MAX) NOT NULL,
+    TransactionHash     STRING(MAX) NOT NULL,
+    TransactionOutIndex INT64 NOT NULL,
+    Spent               BOOL,
+    ObserveTime         TIMESTAMP NOT NULL OPTIONS (allow_commit_timestamp=true),
+) PRIMARY KEY (Address, TransactionHash, TransactionOutIndex),
+  INTERLEAVE IN PARENT Accounts ON DELETE CASCADE;
@@ -0,0 +1,3 @@
+DROP TABLE UnresolvedSpends;
+
+DROP TABLE MissingTransactions;
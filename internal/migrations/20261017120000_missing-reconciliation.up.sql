@@ -0,0 +1,18 @@
+CREATE TABLE MissingTransactions (
+    TransactionHash STRING(MAX) NOT NULL,
+    Height          INT64 NOT NULL,
+    Attempts        INT64 NOT NULL,
+    LastError       STRING(MAX) NOT NULL,
+    Abandoned       BOOL,
+    Resolved        BOOL,
+    ObserveTime     TIMESTAMP NOT NULL OPTIONS (allow_commit_timestamp=true),
+) PRIMARY KEY (TransactionHash);
+
+CREATE TABLE UnresolvedSpends (
+    ConsumedTransactionHash  STRING(MAX) NOT NULL,
+    ConsumedTransactionIndex INT64 NOT NULL,
+    TransactionHash          STRING(MAX) NOT NULL,
+    TransactionIndex         INT64 NOT NULL,
+    Height                   INT64 NOT NULL,
+    ObserveTime              TIMESTAMP NOT NULL OPTIONS (allow_commit_timestamp=true),
+) PRIMARY KEY (ConsumedTransactionHash, ConsumedTransactionIndex);
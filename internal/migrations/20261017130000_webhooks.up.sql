@@ -0,0 +1,23 @@
+CREATE TABLE Webhooks (
+    ID          STRING(MAX) NOT NULL,
+    Owner       STRING(MAX) NOT NULL,
+    URL         STRING(MAX) NOT NULL,
+    Secret      STRING(MAX) NOT NULL,
+    Blocks      BOOL,
+    Addresses   ARRAY<STRING(MAX)>,
+    CreateTime  TIMESTAMP NOT NULL OPTIONS (allow_commit_timestamp=true),
+) PRIMARY KEY (ID);
+
+CREATE INDEX WebhooksByOwner ON Webhooks (Owner);
+
+CREATE TABLE WebhookDeadLetters (
+    ID          STRING(MAX) NOT NULL,
+    DeliveryID  STRING(MAX) NOT NULL,
+    Event       STRING(MAX) NOT NULL,
+    Payload     STRING(MAX) NOT NULL,
+    Attempts    INT64 NOT NULL,
+    LastError   STRING(MAX) NOT NULL,
+    Abandoned   BOOL,
+    ObserveTime TIMESTAMP NOT NULL OPTIONS (allow_commit_timestamp=true),
+) PRIMARY KEY (ID, DeliveryID),
+  INTERLEAVE IN PARENT Webhooks ON DELETE CASCADE;
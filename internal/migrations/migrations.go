@@ -1,13 +1,19 @@
-// Package migrations handles running Spanner emulator/migrations to support testing.
+// Package migrations handles running Spanner emulator/migrations to support testing, and holds the equivalent
+// Postgres and SQLite migrations.
 package migrations
 
 import (
+	"context"
+	"database/sql"
 	"embed"
+	"errors"
 	"fmt"
 	"testing"
 
+	"cloud.google.com/go/spanner"
 	"cloud.google.com/go/spanner/spannertest"
 	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/sqlite"
 	"github.com/golang-migrate/migrate/v4/source"
 	"github.com/golang-migrate/migrate/v4/source/iofs"
 )
@@ -23,6 +29,12 @@ const (
 //go:embed *.sql
 var migrationFiles embed.FS
 
+//go:embed postgres/*.sql
+var postgresMigrationFiles embed.FS
+
+//go:embed sqlite/*.sql
+var sqliteMigrationFiles embed.FS
+
 // SetupEmulator for Spanner in a testing environment.
 func SetupEmulator(t *testing.T) {
 	t.Helper()
@@ -40,6 +52,20 @@ func SetupEmulator(t *testing.T) {
 	srv.SetLogger(t.Logf)
 }
 
+// EmulatorClient connected to the Spanner emulator in a testing environment.
+func EmulatorClient(t *testing.T) *spanner.Client {
+	t.Helper()
+
+	client, err := spanner.NewClient(context.Background(), emulatorDatabase)
+	if err != nil {
+		t.Fatalf("emulator client: %v", err)
+	}
+
+	t.Cleanup(client.Close)
+
+	return client
+}
+
 // RunTestMigrations for Spanner in a testing environment.
 func RunTestMigrations(t *testing.T) {
 	t.Helper()
@@ -97,3 +123,66 @@ func GetMigrations() (source.Driver, error) {
 
 	return d, nil
 }
+
+// RunPostgresMigrations for a given Postgres database URL.
+func RunPostgresMigrations(database string) error {
+	driver, err := GetPostgresMigrations()
+	if err != nil {
+		return fmt.Errorf("running migrations: %w", err)
+	}
+
+	migrations, err := migrate.NewWithSourceInstance("iofs", driver, database)
+	if err != nil {
+		return fmt.Errorf("running migrations: %w", err)
+	}
+
+	if err := migrations.Up(); err != nil {
+		return fmt.Errorf("running migrations: %w", err)
+	}
+
+	return nil
+}
+
+// GetPostgresMigrations from SQL files.
+func GetPostgresMigrations() (source.Driver, error) {
+	d, err := iofs.New(postgresMigrationFiles, "postgres")
+	if err != nil {
+		return nil, fmt.Errorf("getting migrations: %w", err)
+	}
+
+	return d, nil
+}
+
+// RunSQLiteMigrations for an open SQLite database. The database is left open.
+func RunSQLiteMigrations(db *sql.DB) error {
+	driver, err := GetSQLiteMigrations()
+	if err != nil {
+		return fmt.Errorf("running migrations: %w", err)
+	}
+
+	instance, err := sqlite.WithInstance(db, &sqlite.Config{})
+	if err != nil {
+		return fmt.Errorf("running migrations: %w", err)
+	}
+
+	migrations, err := migrate.NewWithInstance("iofs", driver, "sqlite", instance)
+	if err != nil {
+		return fmt.Errorf("running migrations: %w", err)
+	}
+
+	if err := migrations.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("running migrations: %w", err)
+	}
+
+	return nil
+}
+
+// GetSQLiteMigrations from SQL files.
+func GetSQLiteMigrations() (source.Driver, error) {
+	d, err := iofs.New(sqliteMigrationFiles, "sqlite")
+	if err != nil {
+		return nil, fmt.Errorf("getting migrations: %w", err)
+	}
+
+	return d, nil
+}
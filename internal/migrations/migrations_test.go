@@ -1,11 +1,17 @@
 package migrations
 
 import (
+	"database/sql"
+	"embed"
 	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
 	"testing"
 
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/spanner"
+	"github.com/golang-migrate/migrate/v4/database/sqlite"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 )
 
@@ -43,3 +49,145 @@ func TestMigrations(t *testing.T) {
 		t.Fatal("drop:", err)
 	}
 }
+
+func TestMigrationsMatch(t *testing.T) {
+	t.Parallel()
+
+	spannerFiles, err := fs.Glob(migrationFiles, "*.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for dir, files := range map[string]embed.FS{"postgres": postgresMigrationFiles, "sqlite": sqliteMigrationFiles} {
+		matched, err := fs.Glob(files, dir+"/*.sql")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(spannerFiles) != len(matched) {
+			t.Fatalf("%s migration count, spanner: %d, got: %d", dir, len(spannerFiles), len(matched))
+		}
+
+		for i, name := range spannerFiles {
+			if want := path.Join(dir, name); matched[i] != want {
+				t.Errorf("%s migration, want: %s, got: %s", dir, want, matched[i])
+			}
+		}
+	}
+}
+
+func TestSQLiteMigrations(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { db.Close() })
+
+	driver, err := GetSQLiteMigrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	instance, err := sqlite.WithInstance(db, &sqlite.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	migrations, err := migrate.NewWithInstance("iofs", driver, "sqlite", instance)
+	if err != nil {
+		t.Fatal("migrations:", err)
+	}
+
+	if err := migrations.Up(); err != nil {
+		t.Fatal("up:", err)
+	}
+
+	if err := migrations.Down(); err != nil {
+		t.Fatal("down:", err)
+	}
+
+	// Running again on an up to date database does nothing.
+	for i := 0; i < 2; i++ {
+		if err := RunSQLiteMigrations(db); err != nil {
+			t.Fatal("run:", err)
+		}
+	}
+}
+
+func TestSQLiteUnspentOutputsBackfill(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { db.Close() })
+
+	driver, err := GetSQLiteMigrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	instance, err := sqlite.WithInstance(db, &sqlite.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	migrations, err := migrate.NewWithInstance("iofs", driver, "sqlite", instance)
+	if err != nil {
+		t.Fatal("migrations:", err)
+	}
+
+	if err := migrations.Migrate(20261017100000); err != nil {
+		t.Fatal("migrate:", err)
+	}
+
+	// Output 0 of transaction a is spent by transaction b, while output 1 is not.
+	for _, statement := range []string{
+		`INSERT INTO Transactions (Height, TransactionHash, ObserveTime)
+		VALUES (1, 'a', CURRENT_TIMESTAMP), (2, 'b', CURRENT_TIMESTAMP)`,
+		`INSERT INTO Accounts (Address, Balance) VALUES ('owner', '0')`,
+		`INSERT INTO ValueStores (TransactionHash, ChainID, Value, TransactionOutIndex, Owner, Fee, ObserveTime)
+		VALUES ('a', 1, 'ff', 0, 'owner', '0', CURRENT_TIMESTAMP), ('a', 1, 'ff', 1, 'owner', '0', CURRENT_TIMESTAMP)`,
+		`INSERT INTO TransactionInputs (TransactionHash, TransactionIndex, ChainID, ConsumedTransactionHash,
+		ConsumedTransactionIndex, Signature, ObserveTime) VALUES ('b', 0, 1, 'a', 0, 'sig', CURRENT_TIMESTAMP)`,
+	} {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := migrations.Migrate(20261017110000); err != nil {
+		t.Fatal("migrate:", err)
+	}
+
+	var spentHash string
+
+	var spentHeight int64
+
+	if err := db.QueryRow(
+		`SELECT SpentTransactionHash, SpentHeight FROM ValueStores WHERE TransactionHash = 'a' AND Spent`,
+	).Scan(&spentHash, &spentHeight); err != nil {
+		t.Fatal(err)
+	}
+
+	if spentHash != "b" || spentHeight != 2 {
+		t.Errorf("spent, want: b at 2, got: %s at %d", spentHash, spentHeight)
+	}
+
+	var unspent int64
+
+	if err := db.QueryRow(
+		`SELECT TransactionOutIndex FROM AccountOutputs WHERE Address = 'owner' AND Spent IS NOT TRUE`,
+	).Scan(&unspent); err != nil {
+		t.Fatal(err)
+	}
+
+	if unspent != 1 {
+		t.Errorf("unspent output, want: 1, got: %d", unspent)
+	}
+}
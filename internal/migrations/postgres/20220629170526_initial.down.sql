@@ -0,0 +1,15 @@
+DROP TABLE AccountStores;
+
+DROP TABLE AccountTransactions;
+
+DROP TABLE Accounts;
+
+DROP TABLE DataStores;
+
+DROP TABLE ValueStores;
+
+DROP TABLE TransactionInputs;
+
+DROP TABLE Transactions;
+
+DROP TABLE Blocks;
@@ -0,0 +1,79 @@
+CREATE TABLE Blocks (
+    ChainID             BIGINT NOT NULL,
+    Height              BIGINT NOT NULL,
+    TransactionCount    BIGINT NOT NULL,
+    PreviousBlockHash   TEXT NOT NULL,
+    TransactionRootHash TEXT NOT NULL,
+    StateRootHash       TEXT NOT NULL,
+    HeaderRootHash      TEXT NOT NULL,
+    GroupSignatureHash  TEXT NOT NULL,
+    TransactionHashes   TEXT[],
+    ObserveTime         TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (Height)
+);
+
+CREATE TABLE Transactions (
+    Height          BIGINT NOT NULL,
+    TransactionHash TEXT NOT NULL,
+    ObserveTime     TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (TransactionHash)
+);
+
+CREATE TABLE TransactionInputs (
+    TransactionHash          TEXT NOT NULL REFERENCES Transactions ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED,
+    TransactionIndex         BIGINT NOT NULL,
+    ChainID                  BIGINT NOT NULL,
+    ConsumedTransactionHash  TEXT NOT NULL,
+    ConsumedTransactionIndex BIGINT NOT NULL,
+    Signature                TEXT NOT NULL,
+    ObserveTime              TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (TransactionHash, TransactionIndex)
+);
+
+CREATE TABLE ValueStores (
+    TransactionHash     TEXT NOT NULL REFERENCES Transactions ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED,
+    ChainID             BIGINT NOT NULL,
+    Value               TEXT NOT NULL,
+    TransactionOutIndex BIGINT NOT NULL,
+    Owner               TEXT NOT NULL,
+    Fee                 TEXT NOT NULL,
+    ObserveTime         TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (TransactionHash, TransactionOutIndex)
+);
+
+CREATE TABLE DataStores (
+    Signature           TEXT NOT NULL,
+    TransactionHash     TEXT NOT NULL REFERENCES Transactions ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED,
+    ChainID             BIGINT NOT NULL,
+    Index               TEXT NOT NULL,
+    IssuedAt            BIGINT NOT NULL,
+    Deposit             TEXT NOT NULL,
+    RawData             TEXT NOT NULL,
+    TransactionOutIndex BIGINT NOT NULL,
+    Owner               TEXT NOT NULL,
+    Fee                 TEXT NOT NULL,
+    ObserveTime         TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (TransactionHash, TransactionOutIndex)
+);
+
+CREATE TABLE Accounts (
+    Address TEXT NOT NULL,
+    Balance TEXT NOT NULL,
+    PRIMARY KEY (Address)
+);
+
+CREATE TABLE AccountTransactions (
+    Address         TEXT NOT NULL REFERENCES Accounts ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED,
+    TransactionHash TEXT NOT NULL,
+    ObserveTime     TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (Address, TransactionHash)
+);
+
+CREATE TABLE AccountStores (
+    Address         TEXT NOT NULL REFERENCES Accounts ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED,
+    Index           TEXT NOT NULL,
+    Value           TEXT NOT NULL,
+    IssuedAt        BIGINT NOT NULL,
+    ObserveTime     TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (Address, Index)
+);
@@ -0,0 +1,6 @@
+CREATE TABLE Checkpoints (
+    Name        TEXT NOT NULL,
+    Height      BIGINT NOT NULL,
+    ObserveTime TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (Name)
+);
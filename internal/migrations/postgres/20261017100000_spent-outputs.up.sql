@@ -0,0 +1,3 @@
+ALTER TABLE ValueStores ADD COLUMN Spent BOOLEAN;
+
+ALTER TABLE DataStores ADD COLUMN Spent BOOLEAN;
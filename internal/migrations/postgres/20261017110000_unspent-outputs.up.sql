@@ -0,0 +1,48 @@
+ALTER TABLE ValueStores ADD COLUMN SpentTransactionHash TEXT;
+
+ALTER TABLE ValueStores ADD COLUMN SpentInputIndex BIGINT;
+
+ALTER TABLE ValueStores ADD COLUMN SpentHeight BIGINT;
+
+ALTER TABLE DataStores ADD COLUMN SpentTransactionHash TEXT;
+
+ALTER TABLE DataStores ADD COLUMN SpentInputIndex BIGINT;
+
+ALTER TABLE DataStores ADD COLUMN SpentHeight BIGINT;
+
+CREATE TABLE AccountOutputs (
+    Address             TEXT NOT NULL REFERENCES Accounts ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED,
+    TransactionHash     TEXT NOT NULL,
+    TransactionOutIndex BIGINT NOT NULL,
+    Spent               BOOLEAN,
+    ObserveTime         TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (Address, TransactionHash, TransactionOutIndex)
+);
+
+UPDATE ValueStores
+SET Spent = TRUE,
+    SpentTransactionHash = TransactionInputs.TransactionHash,
+    SpentInputIndex = TransactionInputs.TransactionIndex,
+    SpentHeight = Transactions.Height
+FROM TransactionInputs
+JOIN Transactions ON Transactions.TransactionHash = TransactionInputs.TransactionHash
+WHERE TransactionInputs.ConsumedTransactionHash = ValueStores.TransactionHash
+  AND TransactionInputs.ConsumedTransactionIndex = ValueStores.TransactionOutIndex;
+
+UPDATE DataStores
+SET Spent = TRUE,
+    SpentTransactionHash = TransactionInputs.TransactionHash,
+    SpentInputIndex = TransactionInputs.TransactionIndex,
+    SpentHeight = Transactions.Height
+FROM TransactionInputs
+JOIN Transactions ON Transactions.TransactionHash = TransactionInputs.TransactionHash
+WHERE TransactionInputs.ConsumedTransactionHash = DataStores.TransactionHash
+  AND TransactionInputs.ConsumedTransactionIndex = DataStores.TransactionOutIndex;
+
+INSERT INTO AccountOutputs (Address, TransactionHash, TransactionOutIndex, Spent, ObserveTime)
+SELECT Owner, TransactionHash, TransactionOutIndex, Spent, ObserveTime FROM ValueStores
+WHERE Owner IN (SELECT Address FROM Accounts);
+
+INSERT INTO AccountOutputs (Address, TransactionHash, TransactionOutIndex, Spent, ObserveTime)
+SELECT Owner, TransactionHash, TransactionOutIndex, Spent, ObserveTime FROM DataStores
+WHERE Owner IN (SELECT Address FROM Accounts);
@@ -0,0 +1,20 @@
+CREATE TABLE MissingTransactions (
+    TransactionHash TEXT NOT NULL,
+    Height          BIGINT NOT NULL,
+    Attempts        BIGINT NOT NULL,
+    LastError       TEXT NOT NULL,
+    Abandoned       BOOLEAN,
+    Resolved        BOOLEAN,
+    ObserveTime     TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (TransactionHash)
+);
+
+CREATE TABLE UnresolvedSpends (
+    ConsumedTransactionHash  TEXT NOT NULL,
+    ConsumedTransactionIndex BIGINT NOT NULL,
+    TransactionHash          TEXT NOT NULL,
+    TransactionIndex         BIGINT NOT NULL,
+    Height                   BIGINT NOT NULL,
+    ObserveTime              TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (ConsumedTransactionHash, ConsumedTransactionIndex)
+);
@@ -0,0 +1,5 @@
+DROP TABLE WebhookDeadLetters;
+
+DROP INDEX WebhooksByOwner;
+
+DROP TABLE Webhooks;
@@ -0,0 +1,3 @@
+DROP INDEX BlocksByTransactionRootHash;
+
+DROP INDEX BlocksByHeaderRootHash;
@@ -0,0 +1,3 @@
+CREATE INDEX BlocksByHeaderRootHash ON Blocks (HeaderRootHash);
+
+CREATE INDEX BlocksByTransactionRootHash ON Blocks (TransactionRootHash);
@@ -0,0 +1 @@
+ALTER TABLE Transactions ADD COLUMN Missing BOOLEAN;
@@ -0,0 +1,6 @@
+CREATE TABLE Checkpoints (
+    Name        TEXT NOT NULL,
+    Height      INTEGER NOT NULL,
+    ObserveTime TIMESTAMP NOT NULL,
+    PRIMARY KEY (Name)
+);
@@ -0,0 +1,48 @@
+ALTER TABLE ValueStores ADD COLUMN SpentTransactionHash TEXT;
+
+ALTER TABLE ValueStores ADD COLUMN SpentInputIndex INTEGER;
+
+ALTER TABLE ValueStores ADD COLUMN SpentHeight INTEGER;
+
+ALTER TABLE DataStores ADD COLUMN SpentTransactionHash TEXT;
+
+ALTER TABLE DataStores ADD COLUMN SpentInputIndex INTEGER;
+
+ALTER TABLE DataStores ADD COLUMN SpentHeight INTEGER;
+
+CREATE TABLE AccountOutputs (
+    Address             TEXT NOT NULL REFERENCES Accounts ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED,
+    TransactionHash     TEXT NOT NULL,
+    TransactionOutIndex INTEGER NOT NULL,
+    Spent               BOOLEAN,
+    ObserveTime         TIMESTAMP NOT NULL,
+    PRIMARY KEY (Address, TransactionHash, TransactionOutIndex)
+);
+
+UPDATE ValueStores
+SET Spent = TRUE,
+    SpentTransactionHash = TransactionInputs.TransactionHash,
+    SpentInputIndex = TransactionInputs.TransactionIndex,
+    SpentHeight = Transactions.Height
+FROM TransactionInputs
+JOIN Transactions ON Transactions.TransactionHash = TransactionInputs.TransactionHash
+WHERE TransactionInputs.ConsumedTransactionHash = ValueStores.TransactionHash
+  AND TransactionInputs.ConsumedTransactionIndex = ValueStores.TransactionOutIndex;
+
+UPDATE DataStores
+SET Spent = TRUE,
+    SpentTransactionHash = TransactionInputs.TransactionHash,
+    SpentInputIndex = TransactionInputs.TransactionIndex,
+    SpentHeight = Transactions.Height
+FROM TransactionInputs
+JOIN Transactions ON Transactions.TransactionHash = TransactionInputs.TransactionHash
+WHERE TransactionInputs.ConsumedTransactionHash = DataStores.TransactionHash
+  AND TransactionInputs.ConsumedTransactionIndex = DataStores.TransactionOutIndex;
+
+INSERT INTO AccountOutputs (Address, TransactionHash, TransactionOutIndex, Spent, ObserveTime)
+SELECT Owner, TransactionHash, TransactionOutIndex, Spent, ObserveTime FROM ValueStores
+WHERE Owner IN (SELECT Address FROM Accounts);
+
+INSERT INTO AccountOutputs (Address, TransactionHash, TransactionOutIndex, Spent, ObserveTime)
+SELECT Owner, TransactionHash, TransactionOutIndex, Spent, ObserveTime FROM DataStores
+WHERE Owner IN (SELECT Address FROM Accounts);
@@ -0,0 +1,20 @@
+CREATE TABLE MissingTransactions (
+    TransactionHash TEXT NOT NULL,
+    Height          INTEGER NOT NULL,
+    Attempts        INTEGER NOT NULL,
+    LastError       TEXT NOT NULL,
+    Abandoned       BOOLEAN,
+    Resolved        BOOLEAN,
+    ObserveTime     TIMESTAMP NOT NULL,
+    PRIMARY KEY (TransactionHash)
+);
+
+CREATE TABLE UnresolvedSpends (
+    ConsumedTransactionHash  TEXT NOT NULL,
+    ConsumedTransactionIndex INTEGER NOT NULL,
+    TransactionHash          TEXT NOT NULL,
+    TransactionIndex         INTEGER NOT NULL,
+    Height                   INTEGER NOT NULL,
+    ObserveTime              TIMESTAMP NOT NULL,
+    PRIMARY KEY (ConsumedTransactionHash, ConsumedTransactionIndex)
+);
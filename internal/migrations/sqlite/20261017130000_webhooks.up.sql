@@ -0,0 +1,24 @@
+CREATE TABLE Webhooks (
+    ID          TEXT NOT NULL,
+    Owner       TEXT NOT NULL,
+    URL         TEXT NOT NULL,
+    Secret      TEXT NOT NULL,
+    Blocks      BOOLEAN,
+    Addresses   TEXT,
+    CreateTime  TIMESTAMP NOT NULL,
+    PRIMARY KEY (ID)
+);
+
+CREATE INDEX WebhooksByOwner ON Webhooks (Owner);
+
+CREATE TABLE WebhookDeadLetters (
+    ID          TEXT NOT NULL REFERENCES Webhooks ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED,
+    DeliveryID  TEXT NOT NULL,
+    Event       TEXT NOT NULL,
+    Payload     TEXT NOT NULL,
+    Attempts    INTEGER NOT NULL,
+    LastError   TEXT NOT NULL,
+    Abandoned   BOOLEAN,
+    ObserveTime TIMESTAMP NOT NULL,
+    PRIMARY KEY (ID, DeliveryID)
+);
@@ -0,0 +1,95 @@
+// Package netz guards outbound HTTP requests to user supplied URLs, so they can only reach the public internet.
+package netz
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	dialTimeout         = 30 * time.Second
+	keepAlive           = 30 * time.Second
+	idleConnTimeout     = 90 * time.Second
+	tlsHandshakeTimeout = 10 * time.Second
+	maxIdleConns        = 100
+)
+
+// ErrNotPublic is returned for URLs and addresses that aren't on the public internet.
+var ErrNotPublic = errors.New("address is not public")
+
+// sharedAddressSpace used for carrier grade NAT, which isn't reported as private.
+//
+//nolint:gochecknoglobals // Constant prefix
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// Public reports whether an address is routable on the public internet, rather than loopback, private, link-local or
+// otherwise reserved for local use.
+func Public(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !sharedAddressSpace.Contains(addr)
+}
+
+// CheckURL rejects URLs whose host is an address that isn't public, or a name for the local host. Other names are
+// checked once resolved, when dialed by a PublicClient.
+func CheckURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("url: %w", err)
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("%w: %s", ErrNotPublic, host)
+	}
+
+	if addr, err := netip.ParseAddr(host); err == nil && !Public(addr) {
+		return fmt.Errorf("%w: %s", ErrNotPublic, host)
+	}
+
+	return nil
+}
+
+// control rejects connections to addresses that aren't public, once names have been resolved.
+func control(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("dial %s: %w", address, err)
+	}
+
+	if !Public(addrPort.Addr()) {
+		return fmt.Errorf("dial %s: %w", address, ErrNotPublic)
+	}
+
+	return nil
+}
+
+// PublicClient for HTTP requests that only connects to public addresses, without a proxy, and returns redirects
+// rather than following them.
+func PublicClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   dialTimeout,
+		KeepAlive: keepAlive,
+		Control:   control,
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			ForceAttemptHTTP2:   true,
+			MaxIdleConns:        maxIdleConns,
+			IdleConnTimeout:     idleConnTimeout,
+			TLSHandshakeTimeout: tlsHandshakeTimeout,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
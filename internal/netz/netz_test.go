@@ -0,0 +1,92 @@
+package netz
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestPublic(t *testing.T) {
+	t.Parallel()
+
+	for addr, want := range map[string]bool{
+		"8.8.8.8":            true,
+		"2606:4700::1111":    true,
+		"::ffff:8.8.8.8":     true,
+		"127.0.0.1":          false,
+		"::1":                false,
+		"10.1.2.3":           false,
+		"172.16.0.1":         false,
+		"192.168.1.1":        false,
+		"169.254.169.254":    false,
+		"fe80::1":            false,
+		"fc00::1":            false,
+		"100.64.0.1":         false,
+		"0.0.0.0":            false,
+		"224.0.0.1":          false,
+		"::ffff:127.0.0.1":   false,
+		"::ffff:169.254.0.1": false,
+	} {
+		if got := Public(netip.MustParseAddr(addr)); got != want {
+			t.Errorf("%s, want: %v, got: %v", addr, want, got)
+		}
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	t.Parallel()
+
+	for raw, want := range map[string]error{
+		"https://example.com/hook":         nil,
+		"https://8.8.8.8/hook":             nil,
+		"http://localhost:8080":            ErrNotPublic,
+		"http://api.LOCALHOST.":            ErrNotPublic,
+		"http://127.0.0.1/hook":            ErrNotPublic,
+		"http://[::1]:8080":                ErrNotPublic,
+		"http://10.0.0.1":                  ErrNotPublic,
+		"http://169.254.169.254/metadata":  ErrNotPublic,
+		"http://[::ffff:192.168.0.1]:8080": ErrNotPublic,
+	} {
+		if err := CheckURL(raw); !errors.Is(err, want) {
+			t.Errorf("%s, want: %v, got: %v", raw, want, err)
+		}
+	}
+}
+
+func TestPublicClient(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The test server listens on loopback, so can't be reached.
+	resp, err := PublicClient().Do(req)
+	if err == nil {
+		resp.Body.Close()
+	}
+
+	if !errors.Is(err, ErrNotPublic) {
+		t.Errorf("loopback, want: %v, got: %v", ErrNotPublic, err)
+	}
+}
+
+func TestPublicClientRedirect(t *testing.T) {
+	t.Parallel()
+
+	client := PublicClient()
+
+	// Redirects are returned as responses instead of being followed.
+	if err := client.CheckRedirect(nil, nil); !errors.Is(err, http.ErrUseLastResponse) {
+		t.Errorf("redirect, want: %v, got: %v", http.ErrUseLastResponse, err)
+	}
+}
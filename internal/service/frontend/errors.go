@@ -0,0 +1,50 @@
+package frontend
+
+import (
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/alicenet/utilities/internal/logz"
+	"github.com/alicenet/utilities/internal/store"
+)
+
+// storeError converted to a status for a GRPC method, describing the resource it was looking for. Errors that the
+// client can't act on are logged and reported as internal.
+func storeError(err error, resourceType, resourceName string) error {
+	var code codes.Code
+
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		st := status.Newf(codes.NotFound, "%s not found", resourceType)
+
+		st, err := st.WithDetails(&errdetails.ResourceInfo{
+			ResourceType: resourceType,
+			ResourceName: resourceName,
+			Description:  "No " + resourceType + " has been indexed with this name.",
+		})
+		if err != nil {
+			panic(err)
+		}
+
+		return st.Err()
+	case errors.Is(err, store.ErrUnavailable):
+		code = codes.Unavailable
+	case errors.Is(err, store.ErrDeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, store.ErrCanceled):
+		code = codes.Canceled
+	case errors.Is(err, store.ErrConflict):
+		code = codes.Aborted
+	default:
+		logz.WithDetail("err", err).Errorf("getting %s: %v", resourceType, err)
+
+		return status.Errorf(codes.Internal, "internal error")
+	}
+
+	logz.WithDetail("err", err).Warningf("getting %s: %v", resourceType, err)
+
+	return status.Errorf(code, "getting %s: %s", resourceType, code)
+}
@@ -0,0 +1,36 @@
+package frontend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/alicenet/utilities/internal/store"
+)
+
+func TestStoreError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		err  error
+		want codes.Code
+	}{
+		{fmt.Errorf("getting: %w", store.ErrNotFound), codes.NotFound},
+		{fmt.Errorf("getting: %w", store.ErrUnavailable), codes.Unavailable},
+		{fmt.Errorf("getting: %w", store.ErrDeadlineExceeded), codes.DeadlineExceeded},
+		{fmt.Errorf("getting: %w", store.ErrCanceled), codes.Canceled},
+		{fmt.Errorf("getting: %w", store.ErrConflict), codes.Aborted},
+		{context.Canceled, codes.Internal},
+		{errors.New("syntax error"), codes.Internal},
+	}
+
+	for _, tt := range tests {
+		if got := status.Code(storeError(tt.err, "Block", "1")); got != tt.want {
+			t.Errorf("%v, want: %v, got: %v", tt.err, tt.want, got)
+		}
+	}
+}
@@ -3,8 +3,11 @@ package frontend
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
 
-	"cloud.google.com/go/spanner"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -12,7 +15,7 @@ import (
 
 	alicev1 "github.com/alicenet/utilities/api/alice/v1"
 	"github.com/alicenet/utilities/internal/alicenet"
-	"github.com/alicenet/utilities/internal/logz"
+	"github.com/alicenet/utilities/internal/store"
 )
 
 const (
@@ -61,16 +64,48 @@ func validate[ME ~[]error, E fieldError](val validator) error {
 	return st.Err()
 }
 
+// DefaultPollInterval between checks for newly indexed data to send to subscribers.
+const DefaultPollInterval = time.Second
+
 type Service struct {
-	stores *alicenet.Stores
+	stores        *alicenet.Stores
+	pollInterval  time.Duration
+	webhookTokens map[string]string
+}
+
+// An Option to configure the Service.
+type Option func(*Service)
+
+// WithPollInterval between checks for newly indexed data to send to subscribers.
+func WithPollInterval(interval time.Duration) Option {
+	return func(s *Service) {
+		if interval > 0 {
+			s.pollInterval = interval
+		}
+	}
 }
 
-func NewService(stores *alicenet.Stores) *Service {
-	return &Service{
-		stores: stores,
+// WithWebhookTokens that authorize managing webhooks, mapping each bearer token to the owner it acts as. Without any,
+// webhooks can't be managed.
+func WithWebhookTokens(tokens map[string]string) Option {
+	return func(s *Service) {
+		s.webhookTokens = tokens
 	}
 }
 
+func NewService(stores *alicenet.Stores, opts ...Option) *Service {
+	s := &Service{
+		stores:       stores,
+		pollInterval: DefaultPollInterval,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
 func (s *Service) ListStores(
 	ctx context.Context, req *alicev1.ListStoresRequest) (
 	*alicev1.ListStoresResponse, error,
@@ -79,11 +114,9 @@ func (s *Service) ListStores(
 		return nil, err
 	}
 
-	stores, err := s.stores.AccountStores.List(ctx, spanner.Key{req.Address}, maxLimit, 0)
+	stores, err := s.stores.AccountStores.List(ctx, store.Key{req.Address}, maxLimit, 0)
 	if err != nil {
-		logz.WithDetail("err", err).Errorf("getting AccountStore: %v", err)
-
-		return nil, status.Errorf(codes.Internal, "internal error")
+		return nil, storeError(err, "AccountStore", req.Address)
 	}
 
 	resp := &alicev1.ListStoresResponse{}
@@ -104,11 +137,9 @@ func (s *Service) GetStoreValue(
 		return nil, err
 	}
 
-	value, err := s.stores.AccountStores.Get(ctx, spanner.Key{req.Address, req.Index})
+	value, err := s.stores.AccountStores.Get(ctx, store.Key{req.Address, req.Index})
 	if err != nil {
-		logz.WithDetail("err", err).Errorf("getting AccountStore: %v", err)
-
-		return nil, status.Errorf(codes.Internal, "internal error")
+		return nil, storeError(err, "AccountStore", req.Address+"/"+req.Index)
 	}
 
 	resp := &alicev1.GetStoreValueResponse{
@@ -135,14 +166,13 @@ func (s *Service) ListTransactionsForAddress(
 		limit = req.Limit
 	}
 
-	transactions, err := s.stores.AccountTransactions.List(ctx, spanner.Key{req.Address}, limit, req.Offset)
+	transactions, next, err := page(
+		ctx, s.stores.AccountTransactions, store.Key{req.Address}, req.PageToken, limit, req.Offset)
 	if err != nil {
-		logz.WithDetail("err", err).Errorf("getting AccountTransaction: %v", err)
-
-		return nil, status.Errorf(codes.Internal, "internal error")
+		return nil, err
 	}
 
-	resp := &alicev1.ListTransactionsForAddressResponse{}
+	resp := &alicev1.ListTransactionsForAddressResponse{NextPageToken: next}
 
 	for _, v := range transactions {
 		resp.TransactionHashes = append(resp.TransactionHashes, v.TransactionHash)
@@ -162,13 +192,16 @@ func (s *Service) GetBalance(
 		return nil, err
 	}
 
-	account, err := s.stores.Accounts.Get(ctx, spanner.Key{req.Address})
-	if err != nil {
-		logz.WithDetail("err", err).Infof("getting Account: %v", err)
+	// Accounts are only indexed once they hold an output, so until then the balance is zero.
+	account, err := s.stores.Accounts.Get(ctx, store.Key{req.Address})
 
+	switch {
+	case errors.Is(err, store.ErrNotFound):
 		account = alicenet.Account{
 			Balance: "0",
 		}
+	case err != nil:
+		return nil, storeError(err, "Account", req.Address)
 	}
 
 	resp := &alicev1.GetBalanceResponse{
@@ -178,6 +211,54 @@ func (s *Service) GetBalance(
 	return resp, nil
 }
 
+func (s *Service) ListUnspentOutputs(
+	ctx context.Context, req *alicev1.ListUnspentOutputsRequest) (
+	*alicev1.ListUnspentOutputsResponse, error,
+) {
+	if err := validate[
+		alicev1.ListUnspentOutputsRequestMultiError,
+		alicev1.ListUnspentOutputsRequestValidationError,
+	](req); err != nil {
+		return nil, err
+	}
+
+	limit := int64(defaultLimit)
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+
+	outputs, next, err := page(ctx, s.stores.AccountOutputs, store.Key{req.Address}, req.PageToken, limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &alicev1.ListUnspentOutputsResponse{NextPageToken: next}
+
+	for _, v := range outputs {
+		key := store.Key{v.TransactionHash, v.TransactionOutIndex}
+
+		valueStore, err := s.stores.ValueStores.Get(ctx, key)
+
+		switch {
+		case err == nil:
+			resp.Outputs = append(resp.Outputs, valueStoreOutput(valueStore))
+
+			continue
+		case !errors.Is(err, store.ErrNotFound):
+			return nil, storeError(err, "ValueStore", key.String())
+		}
+
+		dataStore, err := s.stores.DataStores.Get(ctx, key)
+		if err != nil {
+			return nil, storeError(err, "DataStore", key.String())
+		}
+
+		resp.Outputs = append(resp.Outputs, dataStoreOutput(dataStore))
+	}
+
+	return resp, nil
+}
+
 func (s *Service) GetTransaction(
 	ctx context.Context, req *alicev1.GetTransactionRequest) (
 	*alicev1.GetTransactionResponse, error,
@@ -191,11 +272,9 @@ func (s *Service) GetTransaction(
 
 	resp := &alicev1.GetTransactionResponse{}
 
-	txn, err := s.stores.Transactions.Get(ctx, spanner.Key{req.Transaction})
+	txn, err := s.stores.Transactions.Get(ctx, store.Key{req.Transaction})
 	if err != nil {
-		logz.WithDetail("err", err).Errorf("getting Transaction: %v", err)
-
-		return nil, status.Errorf(codes.Internal, "internal error")
+		return nil, storeError(err, "Transaction", req.Transaction)
 	}
 
 	if txn.Missing != nil && *txn.Missing {
@@ -205,7 +284,7 @@ func (s *Service) GetTransaction(
 				{
 					Type:        "MissingTransaction",
 					Subject:     txn.TransactionHash,
-					Description: "The transaction has expired and been purged from the database before it could be indexed.",
+					Description: s.missingDescription(ctx, txn.TransactionHash),
 				},
 			},
 		}
@@ -218,82 +297,188 @@ func (s *Service) GetTransaction(
 		return nil, st.Err()
 	}
 
-	resp.Transaction = &alicev1.Transaction{
-		Hash:        txn.TransactionHash,
-		Height:      uint32(txn.Height),
-		ObserveTime: timestamppb.New(txn.ObserveTime),
+	resp.Transaction, err = s.transaction(ctx, txn)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (s *Service) BatchGetTransactions(
+	ctx context.Context, req *alicev1.BatchGetTransactionsRequest) (
+	*alicev1.BatchGetTransactionsResponse, error,
+) {
+	if err := validate[
+		alicev1.BatchGetTransactionsRequestMultiError,
+		alicev1.BatchGetTransactionsRequestValidationError,
+	](req); err != nil {
+		return nil, err
 	}
 
-	inputs, err := s.stores.TransactionInputs.List(ctx, spanner.Key{txn.TransactionHash}, 0, 0)
+	keys := make([]store.Key, len(req.Transactions))
+	for i, hash := range req.Transactions {
+		keys[i] = store.Key{hash}
+	}
+
+	txns, err := s.stores.Transactions.GetMany(ctx, keys)
 	if err != nil {
-		logz.WithDetail("err", err).Errorf("getting TransactionInput: %v", err)
+		return nil, storeError(err, "Transaction", "")
+	}
+
+	indexed := make([]alicenet.Transaction, 0, len(txns))
+	found := make(map[string]bool, len(txns))
+
+	for _, txn := range txns {
+		if txn.Missing != nil && *txn.Missing {
+			continue
+		}
+
+		indexed = append(indexed, txn)
+		found[txn.TransactionHash] = true
+	}
+
+	transactions, err := s.transactions(ctx, indexed)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &alicev1.BatchGetTransactionsResponse{Transactions: transactions}
+
+	for _, hash := range req.Transactions {
+		if !found[hash] {
+			resp.NotFound = append(resp.NotFound, hash)
+		}
+	}
+
+	return resp, nil
+}
+
+// transaction with its inputs and outputs.
+func (s *Service) transaction(ctx context.Context, txn alicenet.Transaction) (*alicev1.Transaction, error) {
+	transactions, err := s.transactions(ctx, []alicenet.Transaction{txn})
+	if err != nil {
+		return nil, err
+	}
+
+	return transactions[0], nil
+}
+
+// transactions with their inputs and outputs, read for every transaction together.
+func (s *Service) transactions(ctx context.Context, txns []alicenet.Transaction) ([]*alicev1.Transaction, error) {
+	transactions := make([]*alicev1.Transaction, len(txns))
+	byHash := make(map[string]*alicev1.Transaction, len(txns))
+	prefixes := make([]store.Key, 0, len(txns))
+
+	for i, txn := range txns {
+		if transaction, ok := byHash[txn.TransactionHash]; ok {
+			transactions[i] = transaction
+
+			continue
+		}
+
+		transactions[i] = &alicev1.Transaction{
+			Hash:        txn.TransactionHash,
+			Height:      uint32(txn.Height),
+			ObserveTime: timestamppb.New(txn.ObserveTime),
+		}
+		byHash[txn.TransactionHash] = transactions[i]
+		prefixes = append(prefixes, store.Key{txn.TransactionHash})
+	}
+
+	if len(prefixes) == 0 {
+		return transactions, nil
+	}
 
-		return nil, status.Errorf(codes.Internal, "internal error")
+	inputs, err := s.stores.TransactionInputs.ListMany(ctx, prefixes)
+	if err != nil {
+		return nil, storeError(err, "TransactionInput", "")
 	}
 
 	for _, input := range inputs {
-		newInput := &alicev1.Transaction_Input{
+		transaction := byHash[input.TransactionHash]
+		transaction.Inputs = append(transaction.Inputs, &alicev1.Transaction_Input{
 			TransactionHash: input.TransactionHash,
 			// ?
 			ChainId:                  uint32(input.ChainID),
 			ConsumedTransactionHash:  input.ConsumedTransactionHash,
 			ConsumedTransactionIndex: input.ConsumedTransactionIndex,
 			Signature:                input.Signature,
-		}
-		resp.Transaction.Inputs = append(resp.Transaction.Inputs, newInput)
+		})
 	}
 
-	dataStores, err := s.stores.DataStores.List(ctx, spanner.Key{txn.TransactionHash}, 0, 0)
+	dataStores, err := s.stores.DataStores.ListMany(ctx, prefixes)
 	if err != nil {
-		logz.WithDetail("err", err).Errorf("getting DataStore: %v", err)
-
-		return nil, status.Errorf(codes.Internal, "internal error")
+		return nil, storeError(err, "DataStore", "")
 	}
 
 	for _, dataStore := range dataStores {
-		newDataStore := &alicev1.Transaction_Output{
-			UnspectTransactionOutput: &alicev1.Transaction_Output_DataStore_{
-				DataStore: &alicev1.Transaction_Output_DataStore{
-					Signature:           dataStore.Signature,
-					TransactionHash:     dataStore.TransactionHash,
-					ChainId:             uint32(dataStore.ChainID),
-					Index:               dataStore.Index,
-					IssuedAt:            uint32(dataStore.IssuedAt),
-					Deposit:             dataStore.Deposit,
-					RawData:             dataStore.RawData,
-					TransactionOutIndex: uint32(dataStore.TransactionOutIndex),
-					Owner:               dataStore.Owner,
-					Fee:                 dataStore.Fee,
-				},
-			},
-		}
-		resp.Transaction.Outputs = append(resp.Transaction.Outputs, newDataStore)
+		transaction := byHash[dataStore.TransactionHash]
+		transaction.Outputs = append(transaction.Outputs, dataStoreOutput(dataStore))
 	}
 
-	valueStores, err := s.stores.ValueStores.List(ctx, spanner.Key{txn.TransactionHash}, 0, 0)
+	valueStores, err := s.stores.ValueStores.ListMany(ctx, prefixes)
 	if err != nil {
-		logz.WithDetail("err", err).Errorf("getting ValueStore: %v", err)
-
-		return nil, status.Errorf(codes.Internal, "internal error")
+		return nil, storeError(err, "ValueStore", "")
 	}
 
 	for _, valueStore := range valueStores {
-		newValueStore := &alicev1.Transaction_Output{
-			UnspectTransactionOutput: &alicev1.Transaction_Output_ValueStore_{
-				ValueStore: &alicev1.Transaction_Output_ValueStore{
-					TransactionHash:     valueStore.TransactionHash,
-					ChainId:             uint32(valueStore.ChainID),
-					Value:               valueStore.Value,
-					TransactionOutIndex: uint32(valueStore.TransactionOutIndex),
-					Owner:               valueStore.Owner,
-					Fee:                 valueStore.Fee,
-				},
+		transaction := byHash[valueStore.TransactionHash]
+		transaction.Outputs = append(transaction.Outputs, valueStoreOutput(valueStore))
+	}
+
+	return transactions, nil
+}
+
+// dataStoreOutput converts a stored DataStore into a transaction output.
+func dataStoreOutput(dataStore alicenet.DataStore) *alicev1.Transaction_Output {
+	return &alicev1.Transaction_Output{
+		UnspectTransactionOutput: &alicev1.Transaction_Output_DataStore_{
+			DataStore: &alicev1.Transaction_Output_DataStore{
+				Signature:           dataStore.Signature,
+				TransactionHash:     dataStore.TransactionHash,
+				ChainId:             uint32(dataStore.ChainID),
+				Index:               dataStore.Index,
+				IssuedAt:            uint32(dataStore.IssuedAt),
+				Deposit:             dataStore.Deposit,
+				RawData:             dataStore.RawData,
+				TransactionOutIndex: uint32(dataStore.TransactionOutIndex),
+				Owner:               dataStore.Owner,
+				Fee:                 dataStore.Fee,
 			},
-		}
-		resp.Transaction.Outputs = append(resp.Transaction.Outputs, newValueStore)
+		},
+		SpentBy: outputSpend(dataStore.SpentTransactionHash, dataStore.SpentInputIndex, dataStore.SpentHeight),
 	}
+}
 
-	return resp, nil
+// valueStoreOutput converts a stored ValueStore into a transaction output.
+func valueStoreOutput(valueStore alicenet.ValueStore) *alicev1.Transaction_Output {
+	return &alicev1.Transaction_Output{
+		UnspectTransactionOutput: &alicev1.Transaction_Output_ValueStore_{
+			ValueStore: &alicev1.Transaction_Output_ValueStore{
+				TransactionHash:     valueStore.TransactionHash,
+				ChainId:             uint32(valueStore.ChainID),
+				Value:               valueStore.Value,
+				TransactionOutIndex: uint32(valueStore.TransactionOutIndex),
+				Owner:               valueStore.Owner,
+				Fee:                 valueStore.Fee,
+			},
+		},
+		SpentBy: outputSpend(valueStore.SpentTransactionHash, valueStore.SpentInputIndex, valueStore.SpentHeight),
+	}
+}
+
+// outputSpend describes the input that spent an output, or nil if it is unspent.
+func outputSpend(hash *string, index, height *int64) *alicev1.Transaction_Output_Spend {
+	if hash == nil || index == nil || height == nil {
+		return nil
+	}
+
+	return &alicev1.Transaction_Output_Spend{
+		TransactionHash: *hash,
+		InputIndex:      *index,
+		Height:          uint32(*height),
+	}
 }
 
 func (s *Service) ListTransactions(
@@ -312,17 +497,27 @@ func (s *Service) ListTransactions(
 		limit = req.Limit
 	}
 
-	txns, err := s.stores.Transactions.List(ctx, nil, limit, req.Offset)
+	txns, next, err := page(ctx, s.stores.Transactions, nil, req.PageToken, limit, req.Offset)
 	if err != nil {
-		logz.WithDetail("err", err).Errorf("getting Transaction: %v", err)
-
-		return nil, status.Errorf(codes.Internal, "internal error")
+		return nil, err
 	}
 
-	resp := &alicev1.ListTransactionsResponse{}
+	resp := &alicev1.ListTransactionsResponse{NextPageToken: next}
+	indexed := make([]alicenet.Transaction, 0, len(txns))
 
 	for _, v := range txns {
 		resp.TransactionHashes = append(resp.TransactionHashes, v.TransactionHash)
+
+		if v.Missing == nil || !*v.Missing {
+			indexed = append(indexed, v)
+		}
+	}
+
+	if req.View == alicev1.View_VIEW_FULL {
+		resp.Transactions, err = s.transactions(ctx, indexed)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return resp, nil
@@ -336,31 +531,109 @@ func (s *Service) GetBlock(
 		return nil, err
 	}
 
-	block, err := s.stores.Blocks.Get(ctx, spanner.Key{int64(req.Height)})
+	block, err := s.stores.Blocks.Get(ctx, store.Key{int64(req.Height)})
 	if err != nil {
-		logz.WithDetail("err", err).Errorf("getting Block: %v", err)
-
-		return nil, status.Errorf(codes.Internal, "internal error")
+		return nil, storeError(err, "Block", strconv.FormatUint(uint64(req.Height), 10))
 	}
 
 	resp := &alicev1.GetBlockResponse{
-		Block: &alicev1.Block{
-			ChainId:             uint32(block.ChainID),
-			Height:              uint32(block.Height),
-			TransactionCount:    uint32(block.TransactionCount),
-			PreviousBlockHash:   block.PreviousBlockHash,
-			TransactionRootHash: block.TransactionRootHash,
-			StateRootHash:       block.StateRootHash,
-			HeaderRootHash:      block.HeaderRootHash,
-			GroupSignatureHash:  block.GroupSignatureHash,
-			TransactionHashes:   block.TransactionHashes,
-			ObserveTime:         timestamppb.New(block.ObserveTime),
-		},
+		Block: blockOutput(block),
 	}
 
 	return resp, nil
 }
 
+func (s *Service) GetBlockByHash(
+	ctx context.Context, req *alicev1.GetBlockByHashRequest) (
+	*alicev1.GetBlockByHashResponse, error,
+) {
+	if err := validate[
+		alicev1.GetBlockByHashRequestMultiError,
+		alicev1.GetBlockByHashRequestValidationError,
+	](req); err != nil {
+		return nil, err
+	}
+
+	block, err := s.blockByHash(ctx, req.Hash)
+	if err != nil {
+		return nil, storeError(err, "Block", req.Hash)
+	}
+
+	resp := &alicev1.GetBlockByHashResponse{
+		Block: blockOutput(block),
+	}
+
+	return resp, nil
+}
+
+// blockByHash finds the earliest block with the header or transaction root hash.
+func (s *Service) blockByHash(ctx context.Context, hash string) (alicenet.Block, error) {
+	blocks, err := s.stores.Blocks.Select(ctx, alicenet.BlocksByHash(hash, 1))
+	if err != nil {
+		return alicenet.Block{}, fmt.Errorf("block by hash: %w", err)
+	}
+
+	if len(blocks) == 0 {
+		return alicenet.Block{}, fmt.Errorf("block by hash %s: %w", hash, store.ErrNotFound)
+	}
+
+	return blocks[0], nil
+}
+
+func (s *Service) BatchGetBlocks(
+	ctx context.Context, req *alicev1.BatchGetBlocksRequest) (
+	*alicev1.BatchGetBlocksResponse, error,
+) {
+	if err := validate[
+		alicev1.BatchGetBlocksRequestMultiError,
+		alicev1.BatchGetBlocksRequestValidationError,
+	](req); err != nil {
+		return nil, err
+	}
+
+	keys := make([]store.Key, len(req.Heights))
+	for i, height := range req.Heights {
+		keys[i] = store.Key{int64(height)}
+	}
+
+	blocks, err := s.stores.Blocks.GetMany(ctx, keys)
+	if err != nil {
+		return nil, storeError(err, "Block", "")
+	}
+
+	resp := &alicev1.BatchGetBlocksResponse{}
+	found := make(map[uint32]bool, len(blocks))
+
+	for _, block := range blocks {
+		resp.Blocks = append(resp.Blocks, blockOutput(block))
+		found[uint32(block.Height)] = true
+	}
+
+	for _, height := range req.Heights {
+		if !found[height] {
+			resp.NotFound = append(resp.NotFound, height)
+		}
+	}
+
+	return resp, nil
+}
+
+// blockOutput converts a stored Block for a response.
+func blockOutput(block alicenet.Block) *alicev1.Block {
+	return &alicev1.Block{
+		ChainId:             uint32(block.ChainID),
+		Height:              uint32(block.Height),
+		TransactionCount:    uint32(block.TransactionCount),
+		PreviousBlockHash:   block.PreviousBlockHash,
+		TransactionRootHash: block.TransactionRootHash,
+		StateRootHash:       block.StateRootHash,
+		HeaderRootHash:      block.HeaderRootHash,
+		GroupSignatureHash:  block.GroupSignatureHash,
+		TransactionHashes:   block.TransactionHashes,
+		ObserveTime:         timestamppb.New(block.ObserveTime),
+	}
+}
+
 func (s *Service) ListBlocks(
 	ctx context.Context, req *alicev1.ListBlocksRequest) (
 	*alicev1.ListBlocksResponse, error,
@@ -377,17 +650,37 @@ func (s *Service) ListBlocks(
 		limit = req.Limit
 	}
 
-	blocks, err := s.stores.Blocks.List(ctx, nil, limit, req.Offset)
+	blocks, next, err := page(ctx, s.stores.Blocks, nil, req.PageToken, limit, req.Offset)
 	if err != nil {
-		logz.WithDetail("err", err).Errorf("getting Block: %v", err)
-
-		return nil, status.Errorf(codes.Internal, "internal error")
+		return nil, err
 	}
 
-	resp := &alicev1.ListBlocksResponse{}
+	resp := &alicev1.ListBlocksResponse{NextPageToken: next}
 	for _, v := range blocks {
 		resp.Heights = append(resp.Heights, uint32(v.Height))
+
+		if req.View == alicev1.View_VIEW_FULL {
+			resp.Blocks = append(resp.Blocks, blockOutput(v))
+		}
 	}
 
 	return resp, nil
 }
+
+// missingDescription explains why a transaction hasn't been indexed, using its reconciliation record if there is one.
+func (s *Service) missingDescription(ctx context.Context, hash string) string {
+	missing, err := s.stores.MissingTransactions.Get(ctx, store.Key{hash})
+	if err != nil {
+		return "The transaction has expired and been purged from the database before it could be indexed."
+	}
+
+	if missing.Abandoned != nil && *missing.Abandoned {
+		return fmt.Sprintf(
+			"The transaction could not be retrieved after %d attempts and will not be retried. Last error: %s",
+			missing.Attempts, missing.LastError)
+	}
+
+	return fmt.Sprintf(
+		"The transaction could not be retrieved after %d attempts and is being retried. Last error: %s",
+		missing.Attempts, missing.LastError)
+}
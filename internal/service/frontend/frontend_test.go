@@ -2,25 +2,401 @@ package frontend
 
 import (
 	"context"
+	"reflect"
+	"strings"
+	"sync/atomic"
 	"testing"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	alicev1 "github.com/alicenet/utilities/api/alice/v1"
+	"github.com/alicenet/utilities/internal/alicenet"
+	"github.com/alicenet/utilities/internal/store"
 )
 
 func TestListStores(t *testing.T) {
 	t.Parallel()
-	t.Skip("skipping until we add memory stores")
 
-	s := Service{}
 	ctx := context.Background()
-	req := &alicev1.ListStoresRequest{
-		Address: "123",
+	stores := alicenet.InMemory()
+	address := strings.Repeat("a", 44)
+
+	for _, index := range []string{"02", "01"} {
+		item := alicenet.AccountStore{Address: address, Index: index, ObserveTime: store.CommitTimestamp}
+		if err := stores.AccountStores.Insert(ctx, item); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := NewService(stores)
+
+	if _, err := s.ListStores(ctx, &alicev1.ListStoresRequest{Address: "123"}); err == nil {
+		t.Error("expected error for invalid address")
+	}
+
+	resp, err := s.ListStores(ctx, &alicev1.ListStoresRequest{Address: address})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Indexes) != 2 || resp.Indexes[0] != "01" || resp.Indexes[1] != "02" {
+		t.Errorf("indexes, want: [01 02], got: %v", resp.Indexes)
+	}
+}
+
+func TestListBlocks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	stores := alicenet.InMemory()
+
+	for height := int64(1); height <= 5; height++ {
+		if err := stores.Blocks.Insert(ctx, alicenet.Block{Height: height}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := NewService(stores)
+
+	var (
+		heights []uint32
+		token   string
+	)
+
+	for pages := 0; ; pages++ {
+		resp, err := s.ListBlocks(ctx, &alicev1.ListBlocksRequest{Limit: 2, PageToken: token})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		heights = append(heights, resp.Heights...)
+
+		// Blocks added at the head don't shift later pages.
+		if pages == 0 {
+			if err := stores.Blocks.Insert(ctx, alicenet.Block{Height: 6}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if resp.NextPageToken == "" {
+			break
+		}
+
+		token = resp.NextPageToken
 	}
-	resp, err := s.ListStores(ctx, req)
 
-	if err == nil {
-		t.Error("expected error")
+	if want := []uint32{5, 4, 3, 2, 1}; !reflect.DeepEqual(heights, want) {
+		t.Errorf("heights, want: %v, got: %v", want, heights)
 	}
 
-	t.Logf("resp: %v\nerr: %v\n", resp, err)
+	resp, err := s.ListBlocks(ctx, &alicev1.ListBlocksRequest{Limit: 2, Offset: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []uint32{5, 4}; !reflect.DeepEqual(resp.Heights, want) {
+		t.Errorf("offset, want: %v, got: %v", want, resp.Heights)
+	}
+
+	if len(resp.Blocks) != 0 {
+		t.Errorf("basic view, want: no blocks, got: %v", resp.Blocks)
+	}
+
+	resp, err = s.ListBlocks(ctx, &alicev1.ListBlocksRequest{Limit: 2, View: alicev1.View_VIEW_FULL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Blocks) != 2 || resp.Blocks[0].Height != 6 || resp.Blocks[1].Height != 5 {
+		t.Errorf("full view, want: blocks [6 5], got: %v", resp.Blocks)
+	}
+
+	_, err = s.ListBlocks(ctx, &alicev1.ListBlocksRequest{PageToken: "not a token"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("invalid page token, want: %v, got: %v", codes.InvalidArgument, err)
+	}
+}
+
+func TestBatchGetBlocks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	stores := alicenet.InMemory()
+
+	for _, height := range []int64{1, 2} {
+		if err := stores.Blocks.Insert(ctx, alicenet.Block{Height: height}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := NewService(stores)
+
+	resp, err := s.BatchGetBlocks(ctx, &alicev1.BatchGetBlocksRequest{Heights: []uint32{2, 3, 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var heights []uint32
+	for _, block := range resp.Blocks {
+		heights = append(heights, block.Height)
+	}
+
+	if want := []uint32{2, 1}; !reflect.DeepEqual(heights, want) {
+		t.Errorf("blocks, want: %v, got: %v", want, heights)
+	}
+
+	if want := []uint32{3}; !reflect.DeepEqual(resp.NotFound, want) {
+		t.Errorf("not found, want: %v, got: %v", want, resp.NotFound)
+	}
+
+	if _, err := s.BatchGetBlocks(ctx, &alicev1.BatchGetBlocksRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("no heights, want: %v, got: %v", codes.InvalidArgument, err)
+	}
+}
+
+func TestGetBlock(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := NewService(alicenet.InMemory())
+
+	_, err := s.GetBlock(ctx, &alicev1.GetBlockRequest{Height: 7})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("want: %v, got: %v", codes.NotFound, err)
+	}
+
+	details := status.Convert(err).Details()
+	if len(details) != 1 {
+		t.Fatalf("details, want: 1 ResourceInfo, got: %v", details)
+	}
+
+	info, ok := details[0].(*errdetails.ResourceInfo)
+	if !ok || info.ResourceType != "Block" || info.ResourceName != "7" {
+		t.Errorf("resource info, want: Block 7, got: %v", details[0])
+	}
+}
+
+func TestGetBlockByHash(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	stores := alicenet.InMemory()
+	header, txns, empty := strings.Repeat("a", 64), strings.Repeat("b", 64), strings.Repeat("c", 64)
+
+	for _, block := range []alicenet.Block{
+		{Height: 3, HeaderRootHash: header, TransactionRootHash: empty},
+		{Height: 4, HeaderRootHash: strings.Repeat("d", 64), TransactionRootHash: txns, TransactionCount: 1},
+	} {
+		if err := stores.Blocks.Insert(ctx, block); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := NewService(stores)
+
+	for hash, want := range map[string]uint32{header: 3, txns: 4} {
+		resp, err := s.GetBlockByHash(ctx, &alicev1.GetBlockByHashRequest{Hash: hash})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if resp.Block.Height != want {
+			t.Errorf("height, want: %d, got: %d", want, resp.Block.Height)
+		}
+	}
+
+	// Empty blocks aren't found by their transaction root, which is the same for every one.
+	for _, hash := range []string{empty, strings.Repeat("e", 64)} {
+		_, err := s.GetBlockByHash(ctx, &alicev1.GetBlockByHashRequest{Hash: hash})
+		if status.Code(err) != codes.NotFound {
+			t.Errorf("unindexed hash, want: %v, got: %v", codes.NotFound, err)
+		}
+	}
+}
+
+func TestGetBalance(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := NewService(alicenet.InMemory())
+
+	resp, err := s.GetBalance(ctx, &alicev1.GetBalanceRequest{Address: strings.Repeat("a", 44)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Balance != "0" {
+		t.Errorf("balance of unindexed account, want: 0, got: %s", resp.Balance)
+	}
+}
+
+func TestListUnspentOutputs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	stores := alicenet.InMemory()
+	address, hash := strings.Repeat("a", 44), strings.Repeat("b", 64)
+	spent := true
+
+	for _, err := range []error{
+		stores.ValueStores.Insert(ctx, alicenet.ValueStore{TransactionHash: hash, Owner: address, Value: "1"}),
+		stores.DataStores.Insert(ctx, alicenet.DataStore{TransactionHash: hash, TransactionOutIndex: 1, Owner: address}),
+		stores.ValueStores.Insert(ctx, alicenet.ValueStore{TransactionHash: hash, TransactionOutIndex: 2, Owner: address}),
+		stores.AccountOutputs.Insert(ctx, alicenet.AccountOutput{Address: address, TransactionHash: hash}),
+		stores.AccountOutputs.Insert(ctx, alicenet.AccountOutput{
+			Address: address, TransactionHash: hash, TransactionOutIndex: 1,
+		}),
+		stores.AccountOutputs.Insert(ctx, alicenet.AccountOutput{
+			Address: address, TransactionHash: hash, TransactionOutIndex: 2, Spent: &spent,
+		}),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := NewService(stores)
+
+	if _, err := s.ListUnspentOutputs(ctx, &alicev1.ListUnspentOutputsRequest{Address: "123"}); err == nil {
+		t.Error("expected error for invalid address")
+	}
+
+	resp, err := s.ListUnspentOutputs(ctx, &alicev1.ListUnspentOutputsRequest{Address: address})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Outputs) != 2 || resp.Outputs[0].GetValueStore().GetValue() != "1" ||
+		resp.Outputs[1].GetDataStore().GetTransactionOutIndex() != 1 {
+		t.Errorf("outputs, want: value store 0 and data store 1, got: %v", resp.Outputs)
+	}
+
+	resp, err = s.ListUnspentOutputs(ctx, &alicev1.ListUnspentOutputsRequest{Address: address, Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Outputs) != 1 || resp.NextPageToken == "" {
+		t.Errorf("first page, want: one output and a page token, got: %v", resp)
+	}
+}
+
+func TestBatchGetTransactions(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	stores := alicenet.InMemory()
+	indexed, missing, unknown := strings.Repeat("a", 64), strings.Repeat("b", 64), strings.Repeat("c", 64)
+	isMissing := true
+
+	for _, txn := range []alicenet.Transaction{
+		{TransactionHash: indexed, Height: 1},
+		{TransactionHash: missing, Height: 1, Missing: &isMissing},
+	} {
+		if err := stores.Transactions.Insert(ctx, txn); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	output := alicenet.ValueStore{TransactionHash: indexed, Value: "1"}
+	if err := stores.ValueStores.Insert(ctx, output); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewService(stores)
+
+	resp, err := s.BatchGetTransactions(ctx, &alicev1.BatchGetTransactionsRequest{
+		Transactions: []string{unknown, missing, indexed},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Transactions) != 1 || resp.Transactions[0].Hash != indexed || len(resp.Transactions[0].Outputs) != 1 {
+		t.Errorf("transactions, want: [%s] with one output, got: %v", indexed, resp.Transactions)
+	}
+
+	if want := []string{unknown, missing}; !reflect.DeepEqual(resp.NotFound, want) {
+		t.Errorf("not found, want: %v, got: %v", want, resp.NotFound)
+	}
+}
+
+// listed counts the List and ListMany calls made to a Store.
+type listed[T store.Storable] struct {
+	store.Store[T]
+	calls atomic.Int32
+}
+
+func (l *listed[T]) List(ctx context.Context, prefix store.Key, limit, offset int64) ([]T, error) {
+	l.calls.Add(1)
+
+	return l.Store.List(ctx, prefix, limit, offset)
+}
+
+func (l *listed[T]) ListMany(ctx context.Context, prefixes []store.Key) ([]T, error) {
+	l.calls.Add(1)
+
+	return l.Store.ListMany(ctx, prefixes)
+}
+
+func TestListTransactions(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	stores := alicenet.InMemory()
+	indexed, missing, other := strings.Repeat("a", 64), strings.Repeat("b", 64), strings.Repeat("0", 64)
+	isMissing := true
+
+	for _, txn := range []alicenet.Transaction{
+		{TransactionHash: indexed, Height: 1},
+		{TransactionHash: missing, Height: 2, Missing: &isMissing},
+		{TransactionHash: other, Height: 1},
+	} {
+		if err := stores.Transactions.Insert(ctx, txn); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, output := range []alicenet.ValueStore{
+		{TransactionHash: indexed, Value: "1"},
+		{TransactionHash: other, Value: "2"},
+		{TransactionHash: other, TransactionOutIndex: 1, Value: "3"},
+	} {
+		if err := stores.ValueStores.Insert(ctx, output); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	inputs := &listed[alicenet.TransactionInput]{Store: stores.TransactionInputs}
+	dataStores := &listed[alicenet.DataStore]{Store: stores.DataStores}
+	valueStores := &listed[alicenet.ValueStore]{Store: stores.ValueStores}
+	stores.TransactionInputs, stores.DataStores, stores.ValueStores = inputs, dataStores, valueStores
+
+	s := NewService(stores)
+
+	resp, err := s.ListTransactions(ctx, &alicev1.ListTransactionsRequest{View: alicev1.View_VIEW_FULL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{missing, indexed, other}; !reflect.DeepEqual(resp.TransactionHashes, want) {
+		t.Errorf("hashes, want: %v, got: %v", want, resp.TransactionHashes)
+	}
+
+	if len(resp.Transactions) != 2 || resp.Transactions[0].Hash != indexed || len(resp.Transactions[0].Outputs) != 1 ||
+		resp.Transactions[1].Hash != other || len(resp.Transactions[1].Outputs) != 2 {
+		t.Errorf("transactions, want: [%s %s] with one and two outputs, got: %v", indexed, other, resp.Transactions)
+	}
+
+	// Inputs and outputs are read once for the whole page.
+	if got := inputs.calls.Load() + dataStores.calls.Load() + valueStores.calls.Load(); got != 3 {
+		t.Errorf("lists, want: 3, got: %d", got)
+	}
+
+	_, err = s.ListTransactions(ctx, &alicev1.ListTransactionsRequest{View: 3})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("undefined view, want: %v, got: %v", codes.InvalidArgument, err)
+	}
 }
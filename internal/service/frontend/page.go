@@ -0,0 +1,107 @@
+package frontend
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/alicenet/utilities/internal/store"
+)
+
+// errPageToken indicates a page token that wasn't issued by this service.
+var errPageToken = errors.New("invalid page token")
+
+// pageToken naming the key of the last item in a page. It is opaque to clients.
+func pageToken(key store.Key) string {
+	b, err := json.Marshal(key)
+	if err != nil {
+		panic(err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// parsePageToken for the key of the last item in the previous page. Keys are made of strings and integers.
+func parsePageToken(token string) (store.Key, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errPageToken, err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+
+	var parts []any
+	if err := decoder.Decode(&parts); err != nil {
+		return nil, fmt.Errorf("%w: %v", errPageToken, err)
+	}
+
+	if len(parts) == 0 {
+		return nil, errPageToken
+	}
+
+	key := make(store.Key, len(parts))
+
+	for i, part := range parts {
+		switch part := part.(type) {
+		case string:
+			key[i] = part
+		case json.Number:
+			n, err := part.Int64()
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", errPageToken, err)
+			}
+
+			key[i] = n
+		default:
+			return nil, fmt.Errorf("%w: unexpected %T", errPageToken, part)
+		}
+	}
+
+	return key, nil
+}
+
+// page of items listed under a prefix, after the last item of the previous page if a page token is given, or at the
+// offset otherwise. A token for the next page is returned if the page is full.
+func page[T store.Storable](
+	ctx context.Context, items store.Store[T], prefix store.Key, token string, limit, offset int64,
+) ([]T, string, error) {
+	var (
+		item   T
+		listed []T
+		err    error
+	)
+
+	if token == "" {
+		listed, err = items.List(ctx, prefix, limit, offset)
+	} else {
+		var after store.Key
+
+		after, err = parsePageToken(token)
+		if err != nil {
+			return nil, "", status.Errorf(codes.InvalidArgument, "invalid page token")
+		}
+
+		listed, err = items.Page(ctx, prefix, after, limit)
+	}
+
+	switch {
+	case token != "" && errors.Is(err, store.ErrNotFound):
+		// The item the token names has since been deleted, so where the page starts is lost.
+		return nil, "", status.Errorf(codes.InvalidArgument, "invalid page token")
+	case err != nil:
+		return nil, "", storeError(err, item.Table(), prefix.String())
+	}
+
+	if int64(len(listed)) < limit {
+		return listed, "", nil
+	}
+
+	return listed, pageToken(listed[len(listed)-1].Key()), nil
+}
@@ -0,0 +1,29 @@
+package frontend
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/alicenet/utilities/internal/store"
+)
+
+func TestPageToken(t *testing.T) {
+	t.Parallel()
+
+	key := store.Key{"a", int64(1)}
+
+	got, err := parsePageToken(pageToken(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, key) {
+		t.Errorf("want: %#v, got: %#v", key, got)
+	}
+
+	for _, token := range []string{"!", pageToken(nil), pageToken(store.Key{1.5}), pageToken(store.Key{true})} {
+		if _, err := parsePageToken(token); err == nil {
+			t.Errorf("want error parsing %q", token)
+		}
+	}
+}
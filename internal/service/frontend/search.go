@@ -0,0 +1,110 @@
+package frontend
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	alicev1 "github.com/alicenet/utilities/api/alice/v1"
+	"github.com/alicenet/utilities/internal/store"
+)
+
+var (
+	//nolint:gochecknoglobals // Compiled once
+	hashPattern = regexp.MustCompile("^[0-9a-fA-F]{64}$")
+	//nolint:gochecknoglobals // Compiled once
+	addressPattern = regexp.MustCompile("^[0-9a-fA-F]{44}$")
+)
+
+func (s *Service) Search(
+	ctx context.Context, req *alicev1.SearchRequest) (
+	*alicev1.SearchResponse, error,
+) {
+	if err := validate[
+		alicev1.SearchRequestMultiError,
+		alicev1.SearchRequestValidationError,
+	](req); err != nil {
+		return nil, err
+	}
+
+	query := strings.TrimSpace(req.Query)
+	resp := &alicev1.SearchResponse{}
+
+	if height, err := strconv.ParseUint(query, 10, 32); err == nil && height > 0 {
+		block, err := s.stores.Blocks.Get(ctx, store.Key{int64(height)})
+		found, err := searched(err, "Block", query)
+		if err != nil {
+			return nil, err
+		}
+
+		if found {
+			resp.Results = append(resp.Results, &alicev1.SearchResponse_Result{
+				Kind:   alicev1.SearchResponse_Result_KIND_BLOCK_HEIGHT,
+				Height: uint32(block.Height),
+			})
+		}
+	}
+
+	query = strings.TrimPrefix(strings.TrimPrefix(query, "0x"), "0X")
+
+	// Block and transaction hashes look the same, so both are searched.
+	if hashPattern.MatchString(query) {
+		block, err := s.blockByHash(ctx, query)
+		found, err := searched(err, "Block", query)
+		if err != nil {
+			return nil, err
+		}
+
+		if found {
+			resp.Results = append(resp.Results, &alicev1.SearchResponse_Result{
+				Kind:   alicev1.SearchResponse_Result_KIND_BLOCK_HASH,
+				Height: uint32(block.Height),
+			})
+		}
+
+		txn, err := s.stores.Transactions.Get(ctx, store.Key{query})
+		found, err = searched(err, "Transaction", query)
+		if err != nil {
+			return nil, err
+		}
+
+		if found {
+			resp.Results = append(resp.Results, &alicev1.SearchResponse_Result{
+				Kind:            alicev1.SearchResponse_Result_KIND_TRANSACTION_HASH,
+				Height:          uint32(txn.Height),
+				TransactionHash: txn.TransactionHash,
+			})
+		}
+	}
+
+	if addressPattern.MatchString(query) {
+		account, err := s.stores.Accounts.Get(ctx, store.Key{query})
+		found, err := searched(err, "Account", query)
+		if err != nil {
+			return nil, err
+		}
+
+		if found {
+			resp.Results = append(resp.Results, &alicev1.SearchResponse_Result{
+				Kind:    alicev1.SearchResponse_Result_KIND_ADDRESS,
+				Address: account.Address,
+			})
+		}
+	}
+
+	return resp, nil
+}
+
+// searched reports whether a read for a search found anything, treating items that aren't found as no result.
+func searched(err error, resourceType, resourceName string) (bool, error) {
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, store.ErrNotFound):
+		return false, nil
+	default:
+		return false, storeError(err, resourceType, resourceName)
+	}
+}
@@ -0,0 +1,67 @@
+package frontend
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	alicev1 "github.com/alicenet/utilities/api/alice/v1"
+	"github.com/alicenet/utilities/internal/alicenet"
+)
+
+func TestSearch(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	stores := alicenet.InMemory()
+	header, txn, shared := strings.Repeat("a", 64), strings.Repeat("b", 64), strings.Repeat("c", 64)
+	address := strings.Repeat("d", 44)
+
+	for _, err := range []error{
+		stores.Blocks.Insert(ctx, alicenet.Block{
+			Height: 12, HeaderRootHash: header, TransactionRootHash: shared, TransactionCount: 1,
+		}),
+		stores.Transactions.Insert(ctx, alicenet.Transaction{TransactionHash: txn, Height: 12}),
+		stores.Transactions.Insert(ctx, alicenet.Transaction{TransactionHash: shared, Height: 13}),
+		stores.Accounts.Insert(ctx, alicenet.Account{Address: address}),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := NewService(stores)
+
+	type result struct {
+		kind   alicev1.SearchResponse_Result_Kind
+		height uint32
+	}
+
+	for query, want := range map[string][]result{
+		"12":       {{alicev1.SearchResponse_Result_KIND_BLOCK_HEIGHT, 12}},
+		"13":       nil,
+		header:     {{alicev1.SearchResponse_Result_KIND_BLOCK_HASH, 12}},
+		"0x" + txn: {{alicev1.SearchResponse_Result_KIND_TRANSACTION_HASH, 12}},
+		shared: {
+			{alicev1.SearchResponse_Result_KIND_BLOCK_HASH, 12},
+			{alicev1.SearchResponse_Result_KIND_TRANSACTION_HASH, 13},
+		},
+		" " + address: {{alicev1.SearchResponse_Result_KIND_ADDRESS, 0}},
+		"unknown":     nil,
+	} {
+		resp, err := s.Search(ctx, &alicev1.SearchRequest{Query: query})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got []result
+		for _, r := range resp.Results {
+			got = append(got, result{r.Kind, r.Height})
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("search %q, want: %v, got: %v", query, want, got)
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package frontend
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/status"
+
+	alicev1 "github.com/alicenet/utilities/api/alice/v1"
+	"github.com/alicenet/utilities/internal/alicenet"
+	"github.com/alicenet/utilities/internal/store"
+)
+
+// subscribeBatch is the most blocks read at once while a subscriber catches up.
+const subscribeBatch = 100
+
+func (s *Service) SubscribeBlocks(
+	req *alicev1.SubscribeBlocksRequest, stream alicev1.AliceService_SubscribeBlocksServer,
+) error {
+	if err := validate[
+		alicev1.SubscribeBlocksRequestMultiError,
+		alicev1.SubscribeBlocksRequestValidationError,
+	](req); err != nil {
+		return err
+	}
+
+	return s.FollowBlocks(stream.Context(), int64(req.StartHeight), func(block alicenet.Block) error {
+		return stream.Send(BlockEvent(block))
+	})
+}
+
+func (s *Service) SubscribeAddress(
+	req *alicev1.SubscribeAddressRequest, stream alicev1.AliceService_SubscribeAddressServer,
+) error {
+	if err := validate[
+		alicev1.SubscribeAddressRequestMultiError,
+		alicev1.SubscribeAddressRequestValidationError,
+	](req); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+
+	return s.FollowBlocks(ctx, int64(req.StartHeight), func(block alicenet.Block) error {
+		for _, hash := range block.TransactionHashes {
+			events, err := s.AddressEvents(ctx, block.Height, hash, req.Addresses)
+			if err != nil {
+				return err
+			}
+
+			for _, event := range events {
+				if err := stream.Send(event); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// FollowBlocks from a start height, or from the next block indexed if it is zero, calling fn with each block in
+// height order until the context ends or fn fails.
+func (s *Service) FollowBlocks(ctx context.Context, start int64, fn func(alicenet.Block) error) error {
+	next := start
+	if next == 0 {
+		latest, err := s.latestHeight(ctx)
+		if err != nil {
+			return err
+		}
+
+		next = latest + 1
+	}
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		blocks, err := s.blocksFrom(ctx, next)
+		if err != nil {
+			return err
+		}
+
+		for _, block := range blocks {
+			if err := fn(block); err != nil {
+				return err
+			}
+
+			next = block.Height + 1
+		}
+
+		// Blocks were found, so the subscriber may still be catching up and there is no need to wait for more.
+		if len(blocks) > 0 {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// BlockEvent sent to subscribers for a newly indexed block.
+func BlockEvent(block alicenet.Block) *alicev1.SubscribeBlocksResponse {
+	return &alicev1.SubscribeBlocksResponse{Block: blockOutput(block)}
+}
+
+// AddressEvents caused by a transaction for each of the addresses it involves, as recorded by the worker in
+// AccountTransactions.
+func (s *Service) AddressEvents(
+	ctx context.Context, height int64, hash string, addresses []string,
+) ([]*alicev1.SubscribeAddressResponse, error) {
+	keys := make([]store.Key, len(addresses))
+	for i, address := range addresses {
+		keys[i] = store.Key{address, hash}
+	}
+
+	involved, err := s.stores.AccountTransactions.GetMany(ctx, keys)
+	if err != nil {
+		return nil, storeError(err, "AccountTransaction", hash)
+	}
+
+	if len(involved) == 0 {
+		return nil, nil
+	}
+
+	activity, err := s.transactionActivity(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*alicev1.SubscribeAddressResponse
+
+	for _, account := range involved {
+		for _, event := range activity.events(account.Address) {
+			event.Address, event.TransactionHash, event.Height = account.Address, hash, uint32(height)
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+// A transactionActivity holds the outputs a transaction created and those it spent.
+type transactionActivity struct {
+	values      []alicenet.ValueStore
+	data        []alicenet.DataStore
+	spentValues []alicenet.ValueStore
+	spentData   []alicenet.DataStore
+}
+
+// transactionActivity read from the outputs and inputs of a transaction.
+func (s *Service) transactionActivity(ctx context.Context, hash string) (transactionActivity, error) {
+	var (
+		activity transactionActivity
+		err      error
+	)
+
+	activity.values, err = s.stores.ValueStores.List(ctx, store.Key{hash}, 0, 0)
+	if err != nil {
+		return activity, storeError(err, "ValueStore", hash)
+	}
+
+	activity.data, err = s.stores.DataStores.List(ctx, store.Key{hash}, 0, 0)
+	if err != nil {
+		return activity, storeError(err, "DataStore", hash)
+	}
+
+	inputs, err := s.stores.TransactionInputs.List(ctx, store.Key{hash}, 0, 0)
+	if err != nil {
+		return activity, storeError(err, "TransactionInput", hash)
+	}
+
+	consumed := make([]store.Key, len(inputs))
+	for i, input := range inputs {
+		consumed[i] = store.Key{input.ConsumedTransactionHash, input.ConsumedTransactionIndex}
+	}
+
+	if len(consumed) == 0 {
+		return activity, nil
+	}
+
+	activity.spentValues, err = s.stores.ValueStores.GetMany(ctx, consumed)
+	if err != nil {
+		return activity, storeError(err, "ValueStore", hash)
+	}
+
+	activity.spentData, err = s.stores.DataStores.GetMany(ctx, consumed)
+	if err != nil {
+		return activity, storeError(err, "DataStore", hash)
+	}
+
+	return activity, nil
+}
+
+// events for an address from the activity of a transaction: value it spent, then value it received, then data it
+// stored.
+func (a transactionActivity) events(address string) []*alicev1.SubscribeAddressResponse {
+	var events []*alicev1.SubscribeAddressResponse
+
+	for _, spent := range a.spentValues {
+		if spent.Owner == address {
+			events = append(events, &alicev1.SubscribeAddressResponse{Event: &alicev1.SubscribeAddressResponse_ValueSpent_{
+				ValueSpent: &alicev1.SubscribeAddressResponse_ValueSpent{
+					ConsumedTransactionHash:  spent.TransactionHash,
+					ConsumedTransactionIndex: uint32(spent.TransactionOutIndex),
+					Value:                    spent.Value,
+				},
+			}})
+		}
+	}
+
+	for _, output := range a.values {
+		if output.Owner == address {
+			events = append(events, &alicev1.SubscribeAddressResponse{Event: &alicev1.SubscribeAddressResponse_ValueReceived_{
+				ValueReceived: &alicev1.SubscribeAddressResponse_ValueReceived{
+					TransactionOutIndex: uint32(output.TransactionOutIndex),
+					Value:               output.Value,
+				},
+			}})
+		}
+	}
+
+	overwritten := make(map[string]bool)
+
+	for _, spent := range a.spentData {
+		if spent.Owner == address {
+			overwritten[spent.Index] = true
+		}
+	}
+
+	for _, output := range a.data {
+		if output.Owner != address {
+			continue
+		}
+
+		written := &alicev1.SubscribeAddressResponse_DataStoreWritten{
+			TransactionOutIndex: uint32(output.TransactionOutIndex),
+			Index:               output.Index,
+			RawData:             output.RawData,
+			IssuedAt:            uint32(output.IssuedAt),
+		}
+
+		if overwritten[output.Index] {
+			events = append(events, &alicev1.SubscribeAddressResponse{
+				Event: &alicev1.SubscribeAddressResponse_DataStoreOverwritten{DataStoreOverwritten: written},
+			})
+		} else {
+			events = append(events, &alicev1.SubscribeAddressResponse{
+				Event: &alicev1.SubscribeAddressResponse_DataStoreWritten_{DataStoreWritten: written},
+			})
+		}
+	}
+
+	return events
+}
+
+// latestHeight of the blocks indexed, or zero if there are none.
+func (s *Service) latestHeight(ctx context.Context) (int64, error) {
+	blocks, err := s.stores.Blocks.List(ctx, nil, 1, 0)
+	if err != nil {
+		return 0, storeError(err, "Block", "")
+	}
+
+	if len(blocks) == 0 {
+		return 0, nil
+	}
+
+	return blocks[0].Height, nil
+}
+
+// blocksFrom a height, up to the first that hasn't been indexed yet. Heights that were never indexed, such as those
+// below where the worker started, are skipped over to the next block indexed above them.
+func (s *Service) blocksFrom(ctx context.Context, height int64) ([]alicenet.Block, error) {
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, status.FromContextError(err).Err()
+		}
+
+		keys := make([]store.Key, subscribeBatch)
+		for i := range keys {
+			keys[i] = store.Key{height + int64(i)}
+		}
+
+		blocks, err := s.stores.Blocks.GetMany(ctx, keys)
+		if err != nil {
+			return nil, storeError(err, "Block", "")
+		}
+
+		if len(blocks) > 0 && blocks[0].Height == height {
+			for i, block := range blocks {
+				if block.Height != height+int64(i) {
+					return blocks[:i], nil
+				}
+			}
+
+			return blocks, nil
+		}
+
+		next, err := s.stores.Blocks.Select(ctx, alicenet.BlocksFrom(height, 1))
+		if err != nil {
+			return nil, storeError(err, "Block", "")
+		}
+
+		if len(next) == 0 {
+			return nil, nil
+		}
+
+		height = next[0].Height
+	}
+}
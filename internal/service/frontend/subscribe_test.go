@@ -0,0 +1,179 @@
+package frontend
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	alicev1 "github.com/alicenet/utilities/api/alice/v1"
+	"github.com/alicenet/utilities/internal/alicenet"
+)
+
+// sent messages from a server stream, passed on to the test as they are sent.
+type sent[M any] struct {
+	grpc.ServerStream
+	ctx      context.Context //nolint:containedctx // Returned as the stream's context.
+	messages chan M
+}
+
+func (s *sent[M]) Context() context.Context {
+	return s.ctx
+}
+
+func (s *sent[M]) Send(m M) error {
+	s.messages <- m
+
+	return nil
+}
+
+func TestSubscribeBlocks(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stores := alicenet.InMemory()
+
+	for height := int64(1); height <= 3; height++ {
+		if err := stores.Blocks.Insert(ctx, alicenet.Block{Height: height}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := NewService(stores, WithPollInterval(time.Millisecond))
+	stream := &sent[*alicev1.SubscribeBlocksResponse]{ctx: ctx, messages: make(chan *alicev1.SubscribeBlocksResponse)}
+	done := make(chan error)
+
+	go func() {
+		done <- s.SubscribeBlocks(&alicev1.SubscribeBlocksRequest{StartHeight: 2}, stream)
+	}()
+
+	for _, want := range []uint32{2, 3, 4, 5, 8, 9} {
+		// Blocks indexed after the subscriber catches up are sent as they arrive, skipping heights never indexed.
+		if want == 4 || want == 8 {
+			for _, height := range []int64{int64(want), int64(want) + 1} {
+				if err := stores.Blocks.Insert(ctx, alicenet.Block{Height: height}); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+
+		if got := (<-stream.messages).Block.Height; got != want {
+			t.Errorf("height, want: %d, got: %d", want, got)
+		}
+	}
+
+	// New subscribers without a start height begin after the latest block.
+	if latest, err := s.latestHeight(ctx); err != nil || latest != 9 {
+		t.Errorf("latest height, want: 9, got: %d %v", latest, err)
+	}
+
+	cancel()
+
+	if err := <-done; status.Code(err) != codes.Canceled {
+		t.Errorf("after cancel, want: %v, got: %v", codes.Canceled, err)
+	}
+}
+
+func TestBlocksFrom(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stores := alicenet.InMemory()
+
+	for _, height := range []int64{1, 500, 1000, 1001} {
+		if err := stores.Blocks.Insert(ctx, alicenet.Block{Height: height}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := NewService(stores)
+
+	blocks, err := s.blocksFrom(ctx, 501)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(blocks) != 2 || blocks[0].Height != 1000 || blocks[1].Height != 1001 {
+		t.Errorf("across gaps, want: [1000 1001], got: %v", blocks)
+	}
+
+	cancel()
+
+	if _, err := s.blocksFrom(ctx, 2); status.Code(err) != codes.Canceled {
+		t.Errorf("after cancel, want: %v, got: %v", codes.Canceled, err)
+	}
+}
+
+func TestSubscribeAddress(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stores := alicenet.InMemory()
+	owner, other := strings.Repeat("a", 44), strings.Repeat("b", 44)
+
+	// "1" pays the owner, "2" spends that to write data and pay another address, and "3" overwrites the data.
+	for _, item := range []any{
+		alicenet.ValueStore{TransactionHash: "1", Owner: owner, Value: "ff"},
+		alicenet.TransactionInput{TransactionHash: "2", ConsumedTransactionHash: "1"},
+		alicenet.DataStore{TransactionHash: "2", Owner: owner, Index: "01", RawData: "aa"},
+		alicenet.ValueStore{TransactionHash: "2", TransactionOutIndex: 1, Owner: other, Value: "ff"},
+		alicenet.TransactionInput{TransactionHash: "3", ConsumedTransactionHash: "2"},
+		alicenet.DataStore{TransactionHash: "3", Owner: owner, Index: "01", RawData: "bb"},
+		alicenet.AccountTransaction{Address: owner, TransactionHash: "1"},
+		alicenet.AccountTransaction{Address: owner, TransactionHash: "2"},
+		alicenet.AccountTransaction{Address: other, TransactionHash: "2"},
+		alicenet.AccountTransaction{Address: owner, TransactionHash: "3"},
+		alicenet.Block{Height: 1, TransactionHashes: []string{"1"}},
+		alicenet.Block{Height: 2, TransactionHashes: []string{"2"}},
+		alicenet.Block{Height: 3, TransactionHashes: []string{"3"}},
+	} {
+		var err error
+
+		switch item := item.(type) {
+		case alicenet.ValueStore:
+			err = stores.ValueStores.Insert(ctx, item)
+		case alicenet.DataStore:
+			err = stores.DataStores.Insert(ctx, item)
+		case alicenet.TransactionInput:
+			err = stores.TransactionInputs.Insert(ctx, item)
+		case alicenet.AccountTransaction:
+			err = stores.AccountTransactions.Insert(ctx, item)
+		case alicenet.Block:
+			err = stores.Blocks.Insert(ctx, item)
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := NewService(stores, WithPollInterval(time.Millisecond))
+	stream := &sent[*alicev1.SubscribeAddressResponse]{ctx: ctx, messages: make(chan *alicev1.SubscribeAddressResponse)}
+
+	go func() {
+		_ = s.SubscribeAddress(&alicev1.SubscribeAddressRequest{Addresses: []string{owner}, StartHeight: 1}, stream)
+	}()
+
+	for _, want := range []struct {
+		hash  string
+		event string
+	}{
+		{"1", "value_received"},
+		{"2", "value_spent"},
+		{"2", "data_store_written"},
+		{"3", "data_store_overwritten"},
+	} {
+		got := <-stream.messages
+		event := got.ProtoReflect().WhichOneof(got.ProtoReflect().Descriptor().Oneofs().ByName("event")).Name()
+
+		if got.Address != owner || got.TransactionHash != want.hash || string(event) != want.event {
+			t.Errorf("event, want: %s %s, got: %s %s", want.hash, want.event, got.TransactionHash, event)
+		}
+	}
+}
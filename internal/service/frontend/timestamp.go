@@ -0,0 +1,35 @@
+package frontend
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/alicenet/utilities/internal/logz"
+	"github.com/alicenet/utilities/internal/store"
+)
+
+// ReadTimestampHeader in response metadata holds the timestamp of the oldest data read by a method, formatted as
+// RFC 3339. It is only sent by stores that report when their data was read, such as Spanner.
+const ReadTimestampHeader = "x-read-timestamp"
+
+// ReadTimestamps intercepts unary methods to send the timestamp of the data they read in response metadata, so
+// clients know how fresh it is.
+func ReadTimestamps(
+	ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+) (any, error) {
+	ctx = store.RecordReadTimestamp(ctx)
+
+	resp, err := handler(ctx, req)
+
+	if read, ok := store.ReadTimestamp(ctx); ok {
+		header := metadata.Pairs(ReadTimestampHeader, read.UTC().Format(time.RFC3339Nano))
+		if err := grpc.SetHeader(ctx, header); err != nil {
+			logz.WithDetail("err", err).Warningf("setting read timestamp: %v", err)
+		}
+	}
+
+	return resp, err
+}
@@ -0,0 +1,246 @@
+package frontend
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	alicev1 "github.com/alicenet/utilities/api/alice/v1"
+	"github.com/alicenet/utilities/internal/alicenet"
+	"github.com/alicenet/utilities/internal/netz"
+	"github.com/alicenet/utilities/internal/store"
+)
+
+const (
+	webhookIDBytes     = 16
+	webhookSecretBytes = 32
+	bearerPrefix       = "Bearer "
+)
+
+func (s *Service) CreateWebhook(
+	ctx context.Context, req *alicev1.CreateWebhookRequest) (
+	*alicev1.Webhook, error,
+) {
+	if err := validate[
+		alicev1.CreateWebhookRequestMultiError,
+		alicev1.CreateWebhookRequestValidationError,
+	](req); err != nil {
+		return nil, err
+	}
+
+	owner, err := s.webhookOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !req.Blocks && len(req.Addresses) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "webhook must receive blocks or address activity")
+	}
+
+	if err := netz.CheckURL(req.Url); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "webhook url: %v", err)
+	}
+
+	webhook := alicenet.Webhook{
+		ID:         randomHex(webhookIDBytes),
+		Owner:      owner,
+		URL:        req.Url,
+		Secret:     randomHex(webhookSecretBytes),
+		Blocks:     &req.Blocks,
+		Addresses:  req.Addresses,
+		CreateTime: store.CommitTimestamp,
+	}
+
+	if err := s.stores.Webhooks.Insert(ctx, webhook); err != nil {
+		return nil, storeError(err, "Webhook", webhook.ID)
+	}
+
+	// The create time is only known once committed, so is left for ListWebhooks.
+	resp := &alicev1.Webhook{
+		Id:        webhook.ID,
+		Url:       webhook.URL,
+		Secret:    webhook.Secret,
+		Blocks:    req.Blocks,
+		Addresses: req.Addresses,
+	}
+
+	return resp, nil
+}
+
+func (s *Service) ListWebhooks(
+	ctx context.Context, req *alicev1.ListWebhooksRequest) (
+	*alicev1.ListWebhooksResponse, error,
+) {
+	if err := validate[
+		alicev1.ListWebhooksRequestMultiError,
+		alicev1.ListWebhooksRequestValidationError,
+	](req); err != nil {
+		return nil, err
+	}
+
+	owner, err := s.webhookOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := int64(defaultLimit)
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+
+	webhooks, next, err := page(ctx, s.stores.Webhooks, store.Key{owner}, req.PageToken, limit, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &alicev1.ListWebhooksResponse{NextPageToken: next}
+	for _, webhook := range webhooks {
+		resp.Webhooks = append(resp.Webhooks, webhookOutput(webhook))
+	}
+
+	return resp, nil
+}
+
+func (s *Service) DeleteWebhook(
+	ctx context.Context, req *alicev1.DeleteWebhookRequest) (
+	*alicev1.DeleteWebhookResponse, error,
+) {
+	if err := validate[
+		alicev1.DeleteWebhookRequestMultiError,
+		alicev1.DeleteWebhookRequestValidationError,
+	](req); err != nil {
+		return nil, err
+	}
+
+	if err := s.ownWebhook(ctx, req.Id); err != nil {
+		return nil, err
+	}
+
+	if err := s.stores.Webhooks.Delete(ctx, store.Key{req.Id}); err != nil {
+		return nil, storeError(err, "Webhook", req.Id)
+	}
+
+	return &alicev1.DeleteWebhookResponse{}, nil
+}
+
+func (s *Service) ListWebhookDeadLetters(
+	ctx context.Context, req *alicev1.ListWebhookDeadLettersRequest) (
+	*alicev1.ListWebhookDeadLettersResponse, error,
+) {
+	if err := validate[
+		alicev1.ListWebhookDeadLettersRequestMultiError,
+		alicev1.ListWebhookDeadLettersRequestValidationError,
+	](req); err != nil {
+		return nil, err
+	}
+
+	if err := s.ownWebhook(ctx, req.Id); err != nil {
+		return nil, err
+	}
+
+	limit := int64(defaultLimit)
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+
+	letters, next, err := page(ctx, s.stores.WebhookDeadLetters, store.Key{req.Id}, req.PageToken, limit, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &alicev1.ListWebhookDeadLettersResponse{NextPageToken: next}
+
+	for _, letter := range letters {
+		resp.DeadLetters = append(resp.DeadLetters, &alicev1.ListWebhookDeadLettersResponse_DeadLetter{
+			DeliveryId:  letter.DeliveryID,
+			Event:       letter.Event,
+			Payload:     letter.Payload,
+			Attempts:    letter.Attempts,
+			LastError:   letter.LastError,
+			ObserveTime: timestamppb.New(letter.ObserveTime),
+			Abandoned:   letter.Abandoned != nil && *letter.Abandoned,
+		})
+	}
+
+	return resp, nil
+}
+
+// webhookOwner authorized by the bearer token in the request metadata.
+func (s *Service) webhookOwner(ctx context.Context) (string, error) {
+	if len(s.webhookTokens) == 0 {
+		return "", status.Errorf(codes.PermissionDenied, "webhooks are not enabled")
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		if !strings.HasPrefix(value, bearerPrefix) {
+			continue
+		}
+
+		token := strings.TrimPrefix(value, bearerPrefix)
+
+		// Every token is compared in constant time, so they can't be guessed from how long a request takes.
+		owner := ""
+
+		for candidate, candidateOwner := range s.webhookTokens {
+			if subtle.ConstantTimeCompare([]byte(token), []byte(candidate)) == 1 {
+				owner = candidateOwner
+			}
+		}
+
+		if owner != "" {
+			return owner, nil
+		}
+	}
+
+	return "", status.Errorf(codes.Unauthenticated, "a valid bearer token is required to manage webhooks")
+}
+
+// ownWebhook checks the webhook exists and is owned by the caller. Webhooks owned by others are reported as not found,
+// so their IDs aren't revealed.
+func (s *Service) ownWebhook(ctx context.Context, id string) error {
+	owner, err := s.webhookOwner(ctx)
+	if err != nil {
+		return err
+	}
+
+	webhook, err := s.stores.Webhooks.Get(ctx, store.Key{id})
+	if err == nil && webhook.Owner != owner {
+		err = fmt.Errorf("webhook %s: %w", id, store.ErrNotFound)
+	}
+
+	if err != nil {
+		return storeError(err, "Webhook", id)
+	}
+
+	return nil
+}
+
+// webhookOutput converts a stored Webhook for a response, without its secret.
+func webhookOutput(webhook alicenet.Webhook) *alicev1.Webhook {
+	return &alicev1.Webhook{
+		Id:         webhook.ID,
+		Url:        webhook.URL,
+		Blocks:     webhook.Blocks != nil && *webhook.Blocks,
+		Addresses:  webhook.Addresses,
+		CreateTime: timestamppb.New(webhook.CreateTime),
+	}
+}
+
+// randomHex string of n random bytes.
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,143 @@
+package frontend
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	alicev1 "github.com/alicenet/utilities/api/alice/v1"
+	"github.com/alicenet/utilities/internal/alicenet"
+	"github.com/alicenet/utilities/internal/store"
+)
+
+// bearer token sent in the incoming request metadata.
+func bearer(ctx context.Context, token string) context.Context {
+	return metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+token))
+}
+
+func TestWebhooks(t *testing.T) {
+	t.Parallel()
+
+	stores := alicenet.InMemory()
+	s := NewService(stores, WithWebhookTokens(map[string]string{"alice-token": "alice", "bob-token": "bob"}))
+	ctx, other := bearer(context.Background(), "alice-token"), bearer(context.Background(), "bob-token")
+
+	_, err := s.CreateWebhook(ctx, &alicev1.CreateWebhookRequest{Url: "https://example.com"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("webhook without events, want: %v, got: %v", codes.InvalidArgument, err)
+	}
+
+	created, err := s.CreateWebhook(ctx, &alicev1.CreateWebhookRequest{Url: "https://example.com", Blocks: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(created.Id) != 2*webhookIDBytes || len(created.Secret) != 2*webhookSecretBytes {
+		t.Errorf("created webhook, want: id and secret set, got: %v", created)
+	}
+
+	letter := alicenet.WebhookDeadLetter{ID: created.Id, DeliveryID: "000000000001-block", Event: "block", Attempts: 5}
+	if err := stores.WebhookDeadLetters.Insert(ctx, letter); err != nil {
+		t.Fatal(err)
+	}
+
+	listed, err := s.ListWebhooks(ctx, &alicev1.ListWebhooksRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Secrets are only returned when a webhook is created.
+	if len(listed.Webhooks) != 1 || listed.Webhooks[0].Id != created.Id || listed.Webhooks[0].Secret != "" {
+		t.Errorf("webhooks, want: [%s] without secret, got: %v", created.Id, listed.Webhooks)
+	}
+
+	letters, err := s.ListWebhookDeadLetters(ctx, &alicev1.ListWebhookDeadLettersRequest{Id: created.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(letters.DeadLetters) != 1 || letters.DeadLetters[0].DeliveryId != letter.DeliveryID {
+		t.Errorf("dead letters, want: [%s], got: %v", letter.DeliveryID, letters.DeadLetters)
+	}
+
+	// Other owners can't see or manage the webhook.
+	if listed, err := s.ListWebhooks(other, &alicev1.ListWebhooksRequest{}); err != nil || len(listed.Webhooks) != 0 {
+		t.Errorf("webhooks of another owner, want: none, got: %v %v", listed, err)
+	}
+
+	_, err = s.ListWebhookDeadLetters(other, &alicev1.ListWebhookDeadLettersRequest{Id: created.Id})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("dead letters of another owner, want: %v, got: %v", codes.NotFound, err)
+	}
+
+	_, err = s.DeleteWebhook(other, &alicev1.DeleteWebhookRequest{Id: created.Id})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("deleting as another owner, want: %v, got: %v", codes.NotFound, err)
+	}
+
+	if _, err := s.DeleteWebhook(ctx, &alicev1.DeleteWebhookRequest{Id: created.Id}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.DeleteWebhook(ctx, &alicev1.DeleteWebhookRequest{Id: created.Id}); status.Code(err) != codes.NotFound {
+		t.Errorf("deleting again, want: %v, got: %v", codes.NotFound, err)
+	}
+
+	// Dead letters are deleted along with their webhook.
+	remaining, err := stores.WebhookDeadLetters.List(ctx, store.Key{created.Id}, 0, 0)
+	if err != nil || len(remaining) != 0 {
+		t.Errorf("dead letters after delete, want: none, got: %v %v", remaining, err)
+	}
+}
+
+func TestWebhooksUnauthorized(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	req := &alicev1.CreateWebhookRequest{Url: "https://example.com", Blocks: true}
+
+	// Without tokens configured, webhooks can't be managed at all.
+	_, err := NewService(alicenet.InMemory()).CreateWebhook(bearer(ctx, "token"), req)
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("no tokens configured, want: %v, got: %v", codes.PermissionDenied, err)
+	}
+
+	s := NewService(alicenet.InMemory(), WithWebhookTokens(map[string]string{"token": "alice"}))
+
+	for name, ctx := range map[string]context.Context{
+		"no token":      ctx,
+		"unknown token": bearer(ctx, "unknown"),
+		"not bearer":    metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "token")),
+	} {
+		if _, err := s.CreateWebhook(ctx, req); status.Code(err) != codes.Unauthenticated {
+			t.Errorf("%s, want: %v, got: %v", name, codes.Unauthenticated, err)
+		}
+
+		if _, err := s.ListWebhooks(ctx, &alicev1.ListWebhooksRequest{}); status.Code(err) != codes.Unauthenticated {
+			t.Errorf("%s listing, want: %v, got: %v", name, codes.Unauthenticated, err)
+		}
+	}
+}
+
+func TestCreateWebhookPrivateURL(t *testing.T) {
+	t.Parallel()
+
+	ctx := bearer(context.Background(), "token")
+	s := NewService(alicenet.InMemory(), WithWebhookTokens(map[string]string{"token": "alice"}))
+
+	for _, url := range []string{
+		"http://localhost:8080/hook",
+		"http://127.0.0.1/hook",
+		"http://[::1]/hook",
+		"http://10.0.0.1/hook",
+		"http://169.254.169.254/latest/meta-data",
+	} {
+		_, err := s.CreateWebhook(ctx, &alicev1.CreateWebhookRequest{Url: url, Blocks: true})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s, want: %v, got: %v", url, codes.InvalidArgument, err)
+		}
+	}
+}
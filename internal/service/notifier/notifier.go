@@ -0,0 +1,576 @@
+// Package notifier implements delivery of indexer events to registered webhooks.
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/alicenet/utilities/internal/alicenet"
+	"github.com/alicenet/utilities/internal/logz"
+	"github.com/alicenet/utilities/internal/netz"
+	"github.com/alicenet/utilities/internal/service/frontend"
+	"github.com/alicenet/utilities/internal/store"
+)
+
+// Headers sent with each webhook request.
+const (
+	EventHeader     = "X-Alice-Event"
+	DeliveryHeader  = "X-Alice-Delivery"
+	SignatureHeader = "X-Alice-Signature"
+)
+
+// Kinds of event sent in the EventHeader.
+const (
+	BlockEvent   = "block"
+	AddressEvent = "address"
+)
+
+// checkpointName identifies the checkpoint row tracking blocks delivered to webhooks.
+const checkpointName = "webhooks"
+
+// DefaultDeliveryPolicy is used to deliver events when none is configured.
+//
+//nolint:gochecknoglobals // Default configuration
+var DefaultDeliveryPolicy = DeliveryPolicy{
+	Attempts:       5,
+	InitialBackoff: time.Second,
+	MaxBackoff:     time.Minute,
+	Timeout:        10 * time.Second,
+}
+
+var errStatus = errors.New("unexpected status")
+
+var (
+	//nolint:gochecknoglobals // Stats exempt
+	deliveries = stats.Int64("webhook_deliveries", "Events delivered to webhooks", "1")
+	//nolint:gochecknoglobals // Stats exempt
+	deadLetters = stats.Int64("webhook_dead_letters", "Events that could not be delivered to webhooks", "1")
+	//nolint:gochecknoglobals // Stats exempt
+	views = []*view.View{
+		{
+			Name:        "webhook_deliveries_count",
+			Measure:     deliveries,
+			Description: "The number of events delivered to webhooks",
+			Aggregation: view.Count(),
+		},
+		{
+			Name:        "webhook_dead_letters_count",
+			Measure:     deadLetters,
+			Description: "The number of events that could not be delivered to webhooks",
+			Aggregation: view.Count(),
+		},
+	}
+	//nolint:gochecknoglobals // Stats exempt
+	setupStats sync.Once
+)
+
+// A DeliveryPolicy controls how each event is sent to a webhook.
+type DeliveryPolicy struct {
+	// Attempts to deliver an event, including the first, before its dead letter is abandoned.
+	Attempts int
+	// InitialBackoff between attempts, doubling after each until MaxBackoff.
+	InitialBackoff time.Duration
+	// MaxBackoff between attempts.
+	MaxBackoff time.Duration
+	// Timeout for each attempt.
+	Timeout time.Duration
+}
+
+// backoff before the given retry, counting from one.
+func (p DeliveryPolicy) backoff(retry int) time.Duration {
+	backoff := p.InitialBackoff
+	for i := 1; i < retry && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+
+	if backoff > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+
+	return backoff
+}
+
+// A Service that follows newly indexed blocks and delivers their events to webhooks, at least once each.
+//
+// Each event is sent once as its block is processed. Events that fail are queued as dead letters and retried in the
+// background, along with the later events for the same webhook until it catches up, so a failing webhook doesn't hold
+// up the others.
+type Service struct {
+	stores       *alicenet.Stores
+	events       *frontend.Service
+	client       *http.Client
+	policy       DeliveryPolicy
+	pollInterval time.Duration
+
+	mu         sync.Mutex
+	backlogged map[string]bool
+}
+
+// An Option to configure the Service.
+type Option func(*Service)
+
+// WithDeliveryPolicy configures how events are sent to webhooks.
+func WithDeliveryPolicy(policy DeliveryPolicy) Option {
+	return func(s *Service) {
+		s.policy = policy
+	}
+}
+
+// WithHTTPClient used to send events to webhooks, in place of one that only connects to public addresses and doesn't
+// follow redirects.
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *Service) {
+		s.client = client
+	}
+}
+
+// WithPollInterval between checks for newly indexed blocks.
+func WithPollInterval(interval time.Duration) Option {
+	return func(s *Service) {
+		if interval > 0 {
+			s.pollInterval = interval
+		}
+	}
+}
+
+// New Service delivering events from stores.
+func New(stores *alicenet.Stores, opts ...Option) *Service {
+	setupStats.Do(func() {
+		for i := range views {
+			if err := view.Register(views[i]); err != nil {
+				panic(err)
+			}
+		}
+	})
+
+	s := &Service{
+		stores:       stores,
+		client:       netz.PublicClient(),
+		policy:       DefaultDeliveryPolicy,
+		pollInterval: frontend.DefaultPollInterval,
+		backlogged:   make(map[string]bool),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.events = frontend.NewService(stores, frontend.WithPollInterval(s.pollInterval))
+
+	return s
+}
+
+// Run the service until the context is done, resuming from the last block delivered after any error.
+func (s *Service) Run(ctx context.Context) {
+	redelivered := make(chan struct{})
+
+	go func() {
+		defer close(redelivered)
+
+		s.redeliver(ctx)
+	}()
+
+	defer func() { <-redelivered }()
+
+	for {
+		err := s.process(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		logz.WithDetail("err", err).Errorf("run error: %v", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.pollInterval):
+		}
+	}
+}
+
+// process blocks from the stored checkpoint, or from the next block indexed if there is none.
+func (s *Service) process(ctx context.Context) error {
+	checkpoint, err := s.stores.Checkpoints.Get(ctx, store.Key{checkpointName})
+
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		logz.Notice("no checkpoint found, starting from the next block")
+
+		if checkpoint, err = s.checkpointLatest(ctx); err != nil {
+			return fmt.Errorf("resuming: %w", err)
+		}
+	case err != nil:
+		return fmt.Errorf("resuming: %w", err)
+	default:
+		logz.WithDetail("height", checkpoint.Height).Notice("resuming from checkpoint")
+	}
+
+	start := checkpoint.Height + 1
+
+	if err := s.events.FollowBlocks(ctx, start, func(block alicenet.Block) error {
+		return s.notify(ctx, block)
+	}); err != nil {
+		return fmt.Errorf("processing: %w", err)
+	}
+
+	return nil
+}
+
+// checkpointLatest block indexed before any are delivered, so that processing resumes after it rather than from
+// whichever block is next once an error interrupts it.
+func (s *Service) checkpointLatest(ctx context.Context) (alicenet.Checkpoint, error) {
+	checkpoint := alicenet.Checkpoint{Name: checkpointName, ObserveTime: store.CommitTimestamp}
+
+	blocks, err := s.stores.Blocks.List(ctx, nil, 1, 0)
+	if err != nil {
+		return checkpoint, fmt.Errorf("latest block: %w", err)
+	}
+
+	if len(blocks) > 0 {
+		checkpoint.Height = blocks[0].Height
+	}
+
+	if err := s.stores.Checkpoints.Insert(ctx, checkpoint); err != nil {
+		return checkpoint, fmt.Errorf("checkpoint: %w", err)
+	}
+
+	return checkpoint, nil
+}
+
+// A delivery of an event to a webhook.
+type delivery struct {
+	id      string
+	event   string
+	payload []byte
+}
+
+// notify every webhook of the events in a block, then checkpoint it.
+func (s *Service) notify(ctx context.Context, block alicenet.Block) error {
+	webhooks, err := s.stores.Webhooks.List(ctx, nil, 0, 0)
+	if err != nil {
+		return fmt.Errorf("notifying: %w", err)
+	}
+
+	pending, err := s.deliveries(ctx, block, webhooks)
+	if err != nil {
+		return fmt.Errorf("notifying: %w", err)
+	}
+
+	// Webhooks are delivered to independently, so a slow one only holds up the rest for one attempt.
+	var group errgroup.Group
+
+	for i := range webhooks {
+		webhook := webhooks[i]
+
+		group.Go(func() error {
+			return s.deliver(ctx, webhook, pending[webhook.ID])
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return fmt.Errorf("notifying: %w", err)
+	}
+
+	checkpoint := alicenet.Checkpoint{
+		Name:        checkpointName,
+		Height:      block.Height,
+		ObserveTime: store.CommitTimestamp,
+	}
+
+	if err := s.stores.Checkpoints.Insert(ctx, checkpoint); err != nil {
+		return fmt.Errorf("notifying: %w", err)
+	}
+
+	return nil
+}
+
+// deliveries for each webhook of the events in a block, keyed by webhook ID. Delivery IDs are derived from the
+// event, so they stay the same if a block is delivered again.
+func (s *Service) deliveries(
+	ctx context.Context, block alicenet.Block, webhooks []alicenet.Webhook,
+) (map[string][]delivery, error) {
+	pending := make(map[string][]delivery)
+	watchers := make(map[string][]string)
+
+	for _, webhook := range webhooks {
+		if webhook.Blocks != nil && *webhook.Blocks {
+			payload, err := protojson.Marshal(frontend.BlockEvent(block))
+			if err != nil {
+				return nil, fmt.Errorf("block event: %w", err)
+			}
+
+			pending[webhook.ID] = append(pending[webhook.ID], delivery{
+				id:      fmt.Sprintf("%012d-block", block.Height),
+				event:   BlockEvent,
+				payload: payload,
+			})
+		}
+
+		for _, address := range webhook.Addresses {
+			watchers[address] = append(watchers[address], webhook.ID)
+		}
+	}
+
+	if len(watchers) == 0 {
+		return pending, nil
+	}
+
+	addresses := make([]string, 0, len(watchers))
+	for address := range watchers {
+		addresses = append(addresses, address)
+	}
+
+	sort.Strings(addresses)
+
+	for _, hash := range block.TransactionHashes {
+		events, err := s.events.AddressEvents(ctx, block.Height, hash, addresses)
+		if err != nil {
+			return nil, fmt.Errorf("address events: %w", err)
+		}
+
+		seen := make(map[string]int)
+
+		for _, event := range events {
+			payload, err := protojson.Marshal(event)
+			if err != nil {
+				return nil, fmt.Errorf("address event: %w", err)
+			}
+
+			d := delivery{
+				id:      fmt.Sprintf("%012d-%s-%s-%d", block.Height, hash, event.Address, seen[event.Address]),
+				event:   AddressEvent,
+				payload: payload,
+			}
+			seen[event.Address]++
+
+			for _, id := range watchers[event.Address] {
+				pending[id] = append(pending[id], d)
+			}
+		}
+	}
+
+	return pending, nil
+}
+
+// deliver events to a webhook, trying each once. Events that fail are queued as dead letters, as are the rest while
+// the webhook has any queued, so they are retried in order in the background.
+func (s *Service) deliver(ctx context.Context, webhook alicenet.Webhook, ds []delivery) error {
+	for _, d := range ds {
+		if s.isBacklogged(webhook.ID) {
+			if err := s.queue(ctx, webhook.ID, d, 0, nil); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		err := s.post(ctx, webhook, d)
+		if err == nil {
+			stats.Record(ctx, deliveries.M(1))
+
+			continue
+		}
+
+		if ctx.Err() != nil {
+			return fmt.Errorf("delivering %s: %w", d.id, ctx.Err())
+		}
+
+		s.setBacklogged(webhook.ID, true)
+
+		if err := s.queue(ctx, webhook.ID, d, 1, err); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// redeliver dead letters as they come due, until the context is done.
+func (s *Service) redeliver(ctx context.Context) {
+	for {
+		if err := s.redeliverDue(ctx); err != nil && ctx.Err() == nil {
+			logz.WithDetail("err", err).Errorf("redelivery error: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.pollInterval):
+		}
+	}
+}
+
+// redeliverDue dead letters of every webhook, independently so a slow one doesn't hold up the rest.
+func (s *Service) redeliverDue(ctx context.Context) error {
+	webhooks, err := s.stores.Webhooks.List(ctx, nil, 0, 0)
+	if err != nil {
+		return fmt.Errorf("redelivering: %w", err)
+	}
+
+	var group errgroup.Group
+
+	for i := range webhooks {
+		webhook := webhooks[i]
+
+		group.Go(func() error {
+			return s.redeliverWebhook(ctx, webhook)
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return fmt.Errorf("redelivering: %w", err)
+	}
+
+	return nil
+}
+
+// redeliverWebhook dead letters in order, stopping at the first that isn't due or fails again. Once none are left to
+// retry, new events are sent to the webhook directly again.
+func (s *Service) redeliverWebhook(ctx context.Context, webhook alicenet.Webhook) error {
+	letters, err := s.stores.WebhookDeadLetters.List(ctx, store.Key{webhook.ID}, 0, 0)
+	if err != nil {
+		return fmt.Errorf("dead letters of %s: %w", webhook.ID, err)
+	}
+
+	for _, letter := range letters {
+		if letter.Abandoned != nil && *letter.Abandoned {
+			continue
+		}
+
+		s.setBacklogged(webhook.ID, true)
+
+		if letter.Attempts > 0 && time.Since(letter.ObserveTime) < s.policy.backoff(int(letter.Attempts)) {
+			return nil
+		}
+
+		d := delivery{id: letter.DeliveryID, event: letter.Event, payload: []byte(letter.Payload)}
+
+		err := s.post(ctx, webhook, d)
+		if err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("delivering %s: %w", d.id, ctx.Err())
+			}
+
+			return s.queue(ctx, webhook.ID, d, letter.Attempts+1, err)
+		}
+
+		stats.Record(ctx, deliveries.M(1))
+
+		if err := s.stores.WebhookDeadLetters.Delete(ctx, letter.Key()); err != nil {
+			return fmt.Errorf("delivered %s: %w", d.id, err)
+		}
+	}
+
+	s.setBacklogged(webhook.ID, false)
+
+	return nil
+}
+
+// queue an event as a dead letter after the given attempts, abandoning it once every attempt has been made. The
+// letter is timestamped by the notifier rather than at commit, as retries are scheduled from it.
+func (s *Service) queue(ctx context.Context, id string, d delivery, attempts int64, err error) error {
+	details := logz.Details{"webhook": id, "delivery": d.id, "attempts": attempts}
+	abandoned := attempts > 0 && attempts >= int64(s.policy.Attempts)
+
+	letter := alicenet.WebhookDeadLetter{
+		ID:          id,
+		DeliveryID:  d.id,
+		Event:       d.event,
+		Payload:     string(d.payload),
+		Attempts:    attempts,
+		Abandoned:   &abandoned,
+		ObserveTime: time.Now().UTC(),
+	}
+
+	switch {
+	case abandoned:
+		logz.WithDetails(details).Errorf("dead letter: %v", err)
+		stats.Record(ctx, deadLetters.M(1))
+	case err != nil:
+		logz.WithDetails(details).Warningf("delivery failed: %v", err)
+	}
+
+	if err != nil {
+		letter.LastError = err.Error()
+	}
+
+	if err := s.stores.WebhookDeadLetters.Insert(ctx, letter); err != nil {
+		return fmt.Errorf("queueing %s: %w", d.id, err)
+	}
+
+	return nil
+}
+
+// isBacklogged reports whether a webhook has dead letters still to retry.
+func (s *Service) isBacklogged(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.backlogged[id]
+}
+
+// setBacklogged records whether a webhook has dead letters still to retry.
+func (s *Service) setBacklogged(id string, backlogged bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if backlogged {
+		s.backlogged[id] = true
+	} else {
+		delete(s.backlogged, id)
+	}
+}
+
+// post an event to a webhook once, signed with its secret.
+func (s *Service) post(ctx context.Context, webhook alicenet.Webhook, d delivery) error {
+	ctx, cancel := context.WithTimeout(ctx, s.policy.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(d.payload))
+	if err != nil {
+		return fmt.Errorf("request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, d.event)
+	req.Header.Set(DeliveryHeader, d.id)
+	req.Header.Set(SignatureHeader, Sign(webhook.Secret, d.payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Drain the body so the connection can be reused.
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%w: %s", errStatus, resp.Status)
+	}
+
+	return nil
+}
+
+// Sign a request body with a webhook secret, as sent in the SignatureHeader.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
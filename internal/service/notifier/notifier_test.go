@@ -0,0 +1,314 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicenet/utilities/internal/alicenet"
+	"github.com/alicenet/utilities/internal/store"
+)
+
+// A request received by a webhook.
+type request struct {
+	event, delivery string
+	signed          bool
+}
+
+// webhookServer recording each request it receives, responding with each status in turn and then the last.
+func webhookServer(t *testing.T, secret string, codes ...int) (*httptest.Server, chan request) {
+	t.Helper()
+
+	var received atomic.Int32
+
+	requests := make(chan request, 100)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+
+		requests <- request{
+			event:    r.Header.Get(EventHeader),
+			delivery: r.Header.Get(DeliveryHeader),
+			signed:   r.Header.Get(SignatureHeader) == Sign(secret, body),
+		}
+
+		n := int(received.Add(1))
+		if n > len(codes) {
+			n = len(codes)
+		}
+
+		w.WriteHeader(codes[n-1])
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, requests
+}
+
+// checkpointed waits for the checkpoint to reach a height.
+func checkpointed(ctx context.Context, t *testing.T, stores *alicenet.Stores, height int64) {
+	t.Helper()
+
+	for {
+		checkpoint, err := stores.Checkpoints.Get(ctx, store.Key{checkpointName})
+		if err != nil && !errors.Is(err, store.ErrNotFound) {
+			t.Fatal(err)
+		}
+
+		if err == nil && checkpoint.Height == height {
+			return
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// deadLettered waits for the dead letters of a webhook to satisfy done, and returns them.
+func deadLettered(
+	ctx context.Context, t *testing.T, stores *alicenet.Stores, id string,
+	done func([]alicenet.WebhookDeadLetter) bool,
+) []alicenet.WebhookDeadLetter {
+	t.Helper()
+
+	for {
+		letters, err := stores.WebhookDeadLetters.List(ctx, store.Key{id}, 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if done(letters) {
+			return letters
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestNotify(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stores := alicenet.InMemory()
+	owner := strings.Repeat("a", 44)
+	blocks := true
+
+	srv, requests := webhookServer(t, "secret", http.StatusNoContent)
+
+	for _, err := range []error{
+		stores.Blocks.Insert(ctx, alicenet.Block{Height: 1}),
+		stores.Blocks.Insert(ctx, alicenet.Block{Height: 2, TransactionHashes: []string{"1"}}),
+		stores.ValueStores.Insert(ctx, alicenet.ValueStore{TransactionHash: "1", Owner: owner, Value: "ff"}),
+		stores.AccountTransactions.Insert(ctx, alicenet.AccountTransaction{Address: owner, TransactionHash: "1"}),
+		stores.Checkpoints.Insert(ctx, alicenet.Checkpoint{Name: checkpointName}),
+		stores.Webhooks.Insert(ctx, alicenet.Webhook{
+			ID: "1", URL: srv.URL, Secret: "secret", Blocks: &blocks, Addresses: []string{owner},
+		}),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The test server listens on loopback, which the default client refuses to connect to.
+	go New(stores, WithPollInterval(time.Millisecond), WithHTTPClient(&http.Client{})).Run(ctx)
+
+	for _, want := range []request{
+		{BlockEvent, "000000000001-block", true},
+		{BlockEvent, "000000000002-block", true},
+		{AddressEvent, "000000000002-1-" + owner + "-0", true},
+	} {
+		if got := <-requests; got != want {
+			t.Errorf("request, want: %+v, got: %+v", want, got)
+		}
+	}
+
+	// The checkpoint is written once every webhook has been notified of a block.
+	checkpointed(ctx, t, stores, 2)
+}
+
+func TestNotifyDeadLetter(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stores := alicenet.InMemory()
+	blocks := true
+
+	srv, requests := webhookServer(t, "secret", http.StatusInternalServerError)
+
+	for _, err := range []error{
+		stores.Blocks.Insert(ctx, alicenet.Block{Height: 1}),
+		stores.Checkpoints.Insert(ctx, alicenet.Checkpoint{Name: checkpointName}),
+		stores.Webhooks.Insert(ctx, alicenet.Webhook{ID: "1", URL: srv.URL, Secret: "secret", Blocks: &blocks}),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	policy := DeliveryPolicy{
+		Attempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, Timeout: time.Second,
+	}
+	go New(stores, WithPollInterval(time.Millisecond), WithHTTPClient(&http.Client{}), WithDeliveryPolicy(policy)).Run(ctx)
+
+	// Every attempt is made with the same delivery ID.
+	for i := 0; i < policy.Attempts; i++ {
+		if got := <-requests; got.delivery != "000000000001-block" {
+			t.Errorf("attempt %d delivery, want: 000000000001-block, got: %s", i, got.delivery)
+		}
+	}
+
+	letters := deadLettered(ctx, t, stores, "1", func(letters []alicenet.WebhookDeadLetter) bool {
+		return len(letters) == 1 && *letters[0].Abandoned
+	})
+
+	if got := letters[0]; got.DeliveryID != "000000000001-block" || got.Attempts != 3 || got.Event != BlockEvent {
+		t.Errorf("dead letter, want: 000000000001-block after 3 attempts, got: %+v", got)
+	}
+}
+
+func TestNotifyRedeliver(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stores := alicenet.InMemory()
+	blocks := true
+
+	srv, requests := webhookServer(t, "secret", http.StatusInternalServerError, http.StatusNoContent)
+
+	for _, err := range []error{
+		stores.Blocks.Insert(ctx, alicenet.Block{Height: 1}),
+		stores.Blocks.Insert(ctx, alicenet.Block{Height: 2}),
+		stores.Checkpoints.Insert(ctx, alicenet.Checkpoint{Name: checkpointName}),
+		stores.Webhooks.Insert(ctx, alicenet.Webhook{ID: "1", URL: srv.URL, Secret: "secret", Blocks: &blocks}),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	policy := DeliveryPolicy{
+		Attempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, Timeout: time.Second,
+	}
+	go New(stores, WithPollInterval(time.Millisecond), WithHTTPClient(&http.Client{}), WithDeliveryPolicy(policy)).Run(ctx)
+
+	// Events queued behind a failed one are retried after it, in order.
+	for _, want := range []string{"000000000001-block", "000000000001-block", "000000000002-block"} {
+		if got := <-requests; got.delivery != want {
+			t.Errorf("delivery, want: %s, got: %s", want, got.delivery)
+		}
+	}
+
+	deadLettered(ctx, t, stores, "1", func(letters []alicenet.WebhookDeadLetter) bool {
+		return len(letters) == 0
+	})
+}
+
+func TestNotifyBacklog(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stores := alicenet.InMemory()
+	blocks := true
+
+	failing, _ := webhookServer(t, "secret", http.StatusInternalServerError)
+	working, requests := webhookServer(t, "secret", http.StatusNoContent)
+
+	for _, err := range []error{
+		stores.Blocks.Insert(ctx, alicenet.Block{Height: 1}),
+		stores.Blocks.Insert(ctx, alicenet.Block{Height: 2}),
+		stores.Checkpoints.Insert(ctx, alicenet.Checkpoint{Name: checkpointName}),
+		stores.Webhooks.Insert(ctx, alicenet.Webhook{ID: "1", URL: failing.URL, Secret: "secret", Blocks: &blocks}),
+		stores.Webhooks.Insert(ctx, alicenet.Webhook{ID: "2", URL: working.URL, Secret: "secret", Blocks: &blocks}),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	policy := DeliveryPolicy{Attempts: 3, InitialBackoff: time.Hour, MaxBackoff: time.Hour, Timeout: time.Second}
+	go New(stores, WithPollInterval(time.Millisecond), WithHTTPClient(&http.Client{}), WithDeliveryPolicy(policy)).Run(ctx)
+
+	// The failing webhook doesn't hold up the other, or the checkpoint.
+	for _, want := range []string{"000000000001-block", "000000000002-block"} {
+		if got := <-requests; got.delivery != want {
+			t.Errorf("delivery, want: %s, got: %s", want, got.delivery)
+		}
+	}
+
+	checkpointed(ctx, t, stores, 2)
+
+	// Once an event fails, the rest are queued behind it without being sent.
+	letters := deadLettered(ctx, t, stores, "1", func(letters []alicenet.WebhookDeadLetter) bool {
+		return len(letters) == 2
+	})
+
+	for i, want := range []int64{1, 0} {
+		if got := letters[i]; got.Attempts != want || *got.Abandoned {
+			t.Errorf("dead letter %s, want: %d attempts pending, got: %+v", got.DeliveryID, want, got)
+		}
+	}
+}
+
+func TestNotifyCheckpointsLatest(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stores := alicenet.InMemory()
+	blocks := true
+
+	srv, requests := webhookServer(t, "secret", http.StatusNoContent)
+
+	for _, err := range []error{
+		stores.Blocks.Insert(ctx, alicenet.Block{Height: 1}),
+		stores.Blocks.Insert(ctx, alicenet.Block{Height: 2}),
+		stores.Webhooks.Insert(ctx, alicenet.Webhook{ID: "1", URL: srv.URL, Secret: "secret", Blocks: &blocks}),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	go New(stores, WithPollInterval(time.Millisecond), WithHTTPClient(&http.Client{})).Run(ctx)
+
+	// Without a checkpoint, the latest block is checkpointed before any are delivered.
+	checkpointed(ctx, t, stores, 2)
+
+	if err := stores.Blocks.Insert(ctx, alicenet.Block{Height: 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := <-requests; got.delivery != "000000000003-block" {
+		t.Errorf("delivery, want: 000000000003-block, got: %s", got.delivery)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+
+	policy := DeliveryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}
+
+	for retry, want := range map[int]time.Duration{
+		1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second,
+	} {
+		if got := policy.backoff(retry); got != want {
+			t.Errorf("backoff %d, want: %v, got: %v", retry, want, got)
+		}
+	}
+}
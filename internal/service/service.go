@@ -12,9 +12,10 @@ import (
 	"google.golang.org/grpc/reflection"
 )
 
-// NewServer set up with GRPC reflection and graceful shutdown on receiving an INT or TERM os signal.
-func NewServer() (context.Context, *grpc.Server) {
-	server := grpc.NewServer(grpc.StatsHandler(&ocgrpc.ServerHandler{}))
+// NewServer set up with GRPC reflection and graceful shutdown on receiving an INT or TERM os signal. Any options are
+// applied after the defaults.
+func NewServer(opts ...grpc.ServerOption) (context.Context, *grpc.Server) {
+	server := grpc.NewServer(append([]grpc.ServerOption{grpc.StatsHandler(&ocgrpc.ServerHandler{})}, opts...)...)
 	reflection.Register(server)
 
 	signals := make(chan os.Signal, 1)
@@ -0,0 +1,62 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opencensus.io/stats"
+
+	"github.com/alicenet/utilities/internal/logz"
+	"github.com/alicenet/utilities/internal/store"
+)
+
+//nolint:gochecknoglobals // Stats exempt
+var chainDiscontinuities = stats.Int64(
+	"chain_discontinuities", "Blocks that did not link to the previously indexed block", "1")
+
+// A DiscontinuityError indicates a block does not follow on from the block indexed before it.
+type DiscontinuityError struct {
+	Height   int
+	Expected string
+	Got      string
+}
+
+// Error detailing where the chain broke.
+func (d *DiscontinuityError) Error() string {
+	return fmt.Sprintf("block %d links to %q, expected %q", d.Height, d.Got, d.Expected)
+}
+
+// verify a block links to the block indexed at the height before it.
+func (s *Service) verify(ctx context.Context, block *fetchedBlock) error {
+	if block.height <= 1 {
+		return nil
+	}
+
+	expected := s.previousHash
+	if expected == "" {
+		previous, err := s.stores.Blocks.Get(ctx, store.Key{int64(block.height - 1)})
+
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			// Indexing was started part way up the chain, so there is nothing to link to.
+			logz.WithDetail("height", block.height).Info("previous block not indexed, skipping linkage check")
+
+			return nil
+		case err != nil:
+			return fmt.Errorf("verifying: %w", err)
+		}
+
+		expected = previous.HeaderRootHash
+	}
+
+	if got := block.header.BClaims.PrevBlock; got != expected {
+		stats.Record(ctx, chainDiscontinuities.M(1))
+		logz.WithDetails(logz.Details{"height": block.height, "expected": expected, "got": got}).
+			Alert("chain discontinuity detected, halting")
+
+		return &DiscontinuityError{Height: block.height, Expected: expected, Got: got}
+	}
+
+	return nil
+}
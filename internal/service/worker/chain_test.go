@@ -0,0 +1,68 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	_ "github.com/golang-migrate/migrate/v4/database/spanner"
+
+	"github.com/alicenet/alicenet/proto"
+	"github.com/alicenet/utilities/internal/alicenet"
+	"github.com/alicenet/utilities/internal/migrations"
+	"github.com/alicenet/utilities/internal/store"
+)
+
+// linkedBlock at a height, pointing back to the given previous header root.
+func linkedBlock(height uint32, previous, root string) *fetchedBlock {
+	return &fetchedBlock{
+		height: int(height),
+		header: &proto.BlockHeader{BClaims: &proto.BClaims{Height: height, PrevBlock: previous, HeaderRoot: root}},
+	}
+}
+
+//nolint:paralleltest // t.Parallel not supported with t.Setenv
+func TestVerify(t *testing.T) {
+	migrations.SetupEmulator(t)
+	migrations.RunTestMigrations(t)
+
+	ctx := context.Background()
+	stores := alicenet.InSpanner(migrations.EmulatorClient(t))
+
+	previous := alicenet.Block{Height: 1, HeaderRootHash: "one", ObserveTime: store.CommitTimestamp}
+	if err := stores.Blocks.Insert(ctx, previous); err != nil {
+		t.Fatal(err)
+	}
+
+	s := New(nil, stores)
+
+	if err := s.verify(ctx, linkedBlock(1, "", "one")); err != nil {
+		t.Errorf("genesis: %v", err)
+	}
+
+	if err := s.verify(ctx, linkedBlock(2, "one", "two")); err != nil {
+		t.Errorf("linked to stored block: %v", err)
+	}
+
+	if err := s.verify(ctx, linkedBlock(5, "four", "five")); err != nil {
+		t.Errorf("previous block not indexed: %v", err)
+	}
+
+	var discontinuity *DiscontinuityError
+
+	err := s.verify(ctx, linkedBlock(2, "other", "two"))
+	if !errors.As(err, &discontinuity) {
+		t.Fatalf("want discontinuity, got: %v", err)
+	}
+
+	if discontinuity.Height != 2 || discontinuity.Expected != "one" || discontinuity.Got != "other" {
+		t.Errorf("discontinuity details, got: %+v", discontinuity)
+	}
+
+	// Once a block is committed, the next is checked against it rather than the stores.
+	s.previousHash = "two"
+
+	if err := s.verify(ctx, linkedBlock(3, "one", "three")); !errors.As(err, &discontinuity) {
+		t.Errorf("want discontinuity against committed block, got: %v", err)
+	}
+}
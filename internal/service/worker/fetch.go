@@ -0,0 +1,174 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/alicenet/alicenet/proto"
+	"github.com/alicenet/utilities/internal/alicenet"
+	"github.com/alicenet/utilities/internal/logz"
+)
+
+// A fetchedBlock holds everything retrieved from alicenet for a height, ready to be committed.
+type fetchedBlock struct {
+	height       int
+	header       *proto.BlockHeader
+	transactions []fetchedTransaction
+}
+
+// A fetchedTransaction from alicenet. The transaction is nil if alicenet didn't have it, with err explaining why.
+type fetchedTransaction struct {
+	hash        string
+	transaction *proto.MinedTransactionResponse
+	err         error
+}
+
+// A fetchResult for a single height.
+type fetchResult struct {
+	block *fetchedBlock
+	err   error
+}
+
+// A limiter bounds the number of requests in flight to alicenet.
+type limiter chan struct{}
+
+// do fn once a slot is available, or return early if the context is done.
+func (l limiter) do(ctx context.Context, fn func()) error {
+	select {
+	case l <- struct{}{}:
+	case <-ctx.Done():
+		return fmt.Errorf("waiting to fetch: %w", ctx.Err())
+	}
+
+	defer func() { <-l }()
+
+	fn()
+
+	return nil
+}
+
+// prefetch blocks from and to the given heights, fetching up to the configured concurrency ahead of the consumer.
+// Results are delivered strictly in height order and the channel is closed after the first error.
+func (s *Service) prefetch(ctx context.Context, from, to int) <-chan fetchResult {
+	out := make(chan fetchResult)
+	pending := make(chan chan fetchResult, s.concurrency)
+	slots := make(limiter, s.concurrency)
+
+	go func() {
+		defer close(pending)
+
+		for height := from; height <= to; height++ {
+			result := make(chan fetchResult, 1)
+
+			select {
+			case pending <- result:
+			case <-ctx.Done():
+				return
+			}
+
+			go func(height int) {
+				block, err := s.fetch(ctx, slots, height)
+				result <- fetchResult{block: block, err: err}
+			}(height)
+		}
+	}()
+
+	go func() {
+		defer close(out)
+
+		for result := range pending {
+			r := <-result
+
+			select {
+			case out <- r:
+			case <-ctx.Done():
+				return
+			}
+
+			if r.err != nil {
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// fetch the block header and all transactions for a height from alicenet.
+func (s *Service) fetch(ctx context.Context, slots limiter, height int) (*fetchedBlock, error) {
+	var (
+		blockHeader *proto.BlockHeader
+		err         error
+	)
+
+	if waitErr := slots.do(ctx, func() {
+		blockHeader, err = s.client.BlockHeader(ctx, uint32(height))
+	}); waitErr != nil {
+		return nil, fmt.Errorf("fetching: %w", waitErr)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("fetching: %w", err)
+	}
+
+	block := &fetchedBlock{
+		height:       height,
+		header:       blockHeader,
+		transactions: make([]fetchedTransaction, len(blockHeader.TxHshLst)),
+	}
+
+	// Stop fetching the rest of the transactions once one fails, as the height will be fetched again.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg     sync.WaitGroup
+		once   sync.Once
+		failed error
+	)
+
+	for i, hash := range blockHeader.TxHshLst {
+		wg.Add(1)
+
+		go func(i int, hash string) {
+			defer wg.Done()
+
+			block.transactions[i].hash = hash
+
+			_ = slots.do(ctx, func() {
+				txn, err := s.client.Transaction(ctx, hash)
+
+				switch {
+				case err == nil:
+					block.transactions[i].transaction = txn
+				case alicenet.NotFound(err):
+					// Transaction may have been purged from the chain. Mark it as missing and let it be reconciled later.
+					logz.WithDetails(logz.Details{"hash": hash, "err": err}).Warning("transaction missing, continuing")
+
+					block.transactions[i].err = err
+				default:
+					// Other errors may not recur, so the block isn't committed without the transaction.
+					once.Do(func() {
+						failed = fmt.Errorf("transaction %s: %w", hash, err)
+
+						cancel()
+					})
+				}
+			})
+		}(i, hash)
+	}
+
+	wg.Wait()
+
+	if failed != nil {
+		return nil, fmt.Errorf("fetching: %w", failed)
+	}
+
+	// A cancelled fetch would otherwise look like every transaction is missing.
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("fetching: %w", err)
+	}
+
+	return block, nil
+}
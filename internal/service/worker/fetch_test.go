@@ -0,0 +1,154 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/alicenet/alicenet/proto"
+	"github.com/alicenet/utilities/internal/alicenet"
+	"github.com/alicenet/utilities/internal/mocks"
+	"github.com/alicenet/utilities/internal/store"
+)
+
+var errUnavailable = errors.New("unavailable")
+
+// jitter to shuffle the order in which concurrent fetches complete.
+func jitter() {
+	//nolint:gosec // Randomness is only used to reorder tests.
+	time.Sleep(time.Duration(rand.Intn(5)) * time.Millisecond)
+}
+
+func TestPrefetchOrder(t *testing.T) {
+	t.Parallel()
+
+	const last = 20
+
+	ctrl := gomock.NewController(t)
+	client := mocks.NewMockInterface(ctrl)
+
+	client.EXPECT().BlockHeader(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, height uint32) (*proto.BlockHeader, error) {
+			jitter()
+
+			return &proto.BlockHeader{
+				BClaims:  &proto.BClaims{Height: height},
+				TxHshLst: []string{fmt.Sprintf("%d-a", height), fmt.Sprintf("%d-b", height)},
+			}, nil
+		}).Times(last)
+
+	client.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, hash string) (*proto.MinedTransactionResponse, error) {
+			jitter()
+
+			if hash == "7-b" {
+				return nil, alicenet.APIError{Status: "404 Not Found", StatusCode: http.StatusNotFound}
+			}
+
+			return &proto.MinedTransactionResponse{}, nil
+		}).Times(last * 2)
+
+	s := New(client, nil, WithFetchConcurrency(3))
+	want := 1
+
+	for result := range s.prefetch(context.Background(), 1, last) {
+		if result.err != nil {
+			t.Fatal(result.err)
+		}
+
+		if result.block.height != want {
+			t.Fatalf("out of order, want: %d, got: %d", want, result.block.height)
+		}
+
+		for i, txn := range result.block.transactions {
+			if txn.hash != result.block.header.TxHshLst[i] {
+				t.Errorf("transaction order, want: %s, got: %s", result.block.header.TxHshLst[i], txn.hash)
+			}
+
+			if missing := txn.transaction == nil; missing != (txn.hash == "7-b") {
+				t.Errorf("transaction %s missing: %v", txn.hash, missing)
+			}
+		}
+
+		want++
+	}
+
+	if want != last+1 {
+		t.Errorf("blocks delivered, want: %d, got: %d", last, want-1)
+	}
+}
+
+func TestPrefetchStopsOnError(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	client := mocks.NewMockInterface(ctrl)
+
+	client.EXPECT().BlockHeader(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, height uint32) (*proto.BlockHeader, error) {
+			if height == 3 {
+				return nil, errUnavailable
+			}
+
+			return &proto.BlockHeader{BClaims: &proto.BClaims{Height: height}}, nil
+		}).AnyTimes()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := New(client, nil, WithFetchConcurrency(2))
+
+	var results []fetchResult
+
+	for result := range s.prefetch(ctx, 1, 10) {
+		results = append(results, result)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("results, want: %d, got: %d", 3, len(results))
+	}
+
+	if !errors.Is(results[2].err, errUnavailable) {
+		t.Errorf("want: %v, got: %v", errUnavailable, results[2].err)
+	}
+}
+
+func TestProcessCircuitOpen(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	stores := alicenet.InMemory()
+	ctrl := gomock.NewController(t)
+	client := mocks.NewMockInterface(ctrl)
+
+	client.EXPECT().Height(gomock.Any()).Return(uint32(1), nil)
+	client.EXPECT().BlockHeader(gomock.Any(), uint32(1)).Return(&proto.BlockHeader{
+		BClaims:  &proto.BClaims{Height: 1},
+		TxHshLst: []string{"a"},
+	}, nil)
+	client.EXPECT().Transaction(gomock.Any(), "a").Return(nil, fmt.Errorf("transaction: %w", alicenet.ErrCircuitOpen))
+
+	s := New(client, stores)
+	if err := s.process(ctx); !errors.Is(err, alicenet.ErrCircuitOpen) {
+		t.Fatalf("want: %v, got: %v", alicenet.ErrCircuitOpen, err)
+	}
+
+	// The block is left to be fetched again, rather than committed with its transaction missing.
+	if _, err := stores.Checkpoints.Get(ctx, store.Key{checkpointName}); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("checkpoint, want: %v, got: %v", store.ErrNotFound, err)
+	}
+
+	if missing, err := stores.MissingTransactions.List(ctx, nil, 0, 0); err != nil || len(missing) != 0 {
+		t.Errorf("missing transactions, want: none, got: %v %v", missing, err)
+	}
+
+	if s.highest != 0 {
+		t.Errorf("highest, want: 0, got: %d", s.highest)
+	}
+}
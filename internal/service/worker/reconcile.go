@@ -0,0 +1,161 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opencensus.io/stats"
+
+	"github.com/alicenet/alicenet/proto"
+	"github.com/alicenet/utilities/internal/alicenet"
+	"github.com/alicenet/utilities/internal/logz"
+	"github.com/alicenet/utilities/internal/store"
+)
+
+// DefaultReconcilePolicy is used to retry missing transactions when none is configured.
+//
+//nolint:gochecknoglobals // Default configuration
+var DefaultReconcilePolicy = ReconcilePolicy{
+	Interval:    time.Minute,
+	MaxAttempts: 10,
+	BatchSize:   100,
+}
+
+//nolint:gochecknoglobals // Stats exempt
+var reconciledTransactions = stats.Int64("reconciled_transactions", "Missing transactions that were recovered", "1")
+
+// A ReconcilePolicy controls how transactions that could not be fetched are retried.
+type ReconcilePolicy struct {
+	// Interval between reconciliation passes. Zero disables reconciliation.
+	Interval time.Duration
+	// MaxAttempts to fetch a transaction, including the first, before it is abandoned.
+	MaxAttempts int64
+	// BatchSize of missing transactions retried on each pass.
+	BatchSize int64
+}
+
+// WithReconcilePolicy configures how missing transactions are retried in the background.
+func WithReconcilePolicy(policy ReconcilePolicy) Option {
+	return func(s *Service) {
+		s.reconcile = policy
+	}
+}
+
+// reconcileLoop retries missing transactions every interval until the context is done.
+func (s *Service) reconcileLoop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.reconcile.Interval):
+		}
+
+		if err := s.reconcileOnce(ctx); err != nil {
+			logz.WithDetail("err", err).Errorf("reconcile error: %v", err)
+		}
+	}
+}
+
+// reconcileOnce retries a batch of missing transactions, recording the outcome of each attempt.
+func (s *Service) reconcileOnce(ctx context.Context) error {
+	missing, err := s.stores.MissingTransactions.List(ctx, nil, s.reconcile.BatchSize, 0)
+	if err != nil {
+		return fmt.Errorf("reconciling: %w", err)
+	}
+
+	for _, m := range missing {
+		txn, err := s.client.Transaction(ctx, m.TransactionHash)
+		if ctx.Err() != nil {
+			return fmt.Errorf("reconciling: %w", ctx.Err())
+		}
+
+		if err != nil {
+			if err := s.pushFailedAttempt(ctx, m, err); err != nil {
+				return fmt.Errorf("reconciling: %w", err)
+			}
+
+			continue
+		}
+
+		if err := s.stores.InTransaction(ctx, func(ctx context.Context) error {
+			return s.pushReconciled(ctx, m, txn)
+		}); err != nil {
+			return fmt.Errorf("reconciling: %w", err)
+		}
+
+		logz.WithDetails(logz.Details{"hash": m.TransactionHash, "attempts": m.Attempts + 1}).
+			Notice("reconciled missing transaction")
+		stats.Record(ctx, reconciledTransactions.M(1))
+	}
+
+	return nil
+}
+
+// pushFailedAttempt for a missing transaction, abandoning it once the policy's attempts are exhausted.
+func (s *Service) pushFailedAttempt(ctx context.Context, m alicenet.MissingTransaction, fetchErr error) error {
+	m.Attempts++
+	m.LastError = fetchErr.Error()
+	m.ObserveTime = store.CommitTimestamp
+
+	if m.Attempts >= s.reconcile.MaxAttempts {
+		abandoned := true
+		m.Abandoned = &abandoned
+
+		logz.WithDetails(logz.Details{"hash": m.TransactionHash, "attempts": m.Attempts, "err": fetchErr}).
+			Warning("abandoning missing transaction")
+	}
+
+	if err := s.stores.MissingTransactions.Insert(ctx, m); err != nil {
+		return fmt.Errorf("missing transaction: %w", err)
+	}
+
+	return nil
+}
+
+// pushReconciled transaction at the height it was mined, applying any spends of its outputs seen while it was missing.
+func (s *Service) pushReconciled(
+	ctx context.Context,
+	m alicenet.MissingTransaction,
+	txn *proto.MinedTransactionResponse,
+) error {
+	if err := s.pushTransaction(ctx, int(m.Height), m.TransactionHash, txn); err != nil {
+		return err
+	}
+
+	if err := s.resolveSpends(ctx, m.TransactionHash); err != nil {
+		return err
+	}
+
+	resolved := true
+	m.Attempts++
+	m.Resolved = &resolved
+	m.ObserveTime = store.CommitTimestamp
+
+	if err := s.stores.MissingTransactions.Insert(ctx, m); err != nil {
+		return fmt.Errorf("missing transaction: %w", err)
+	}
+
+	return nil
+}
+
+// resolveSpends of a recovered transaction's outputs by inputs that were indexed before it.
+func (s *Service) resolveSpends(ctx context.Context, hash string) error {
+	spends, err := s.stores.UnresolvedSpends.List(ctx, store.Key{hash}, 0, 0)
+	if err != nil {
+		return fmt.Errorf("resolving spends: %w", err)
+	}
+
+	for _, spend := range spends {
+		input, err := s.stores.TransactionInputs.Get(ctx, store.Key{spend.TransactionHash, spend.TransactionIndex})
+		if err != nil {
+			return fmt.Errorf("resolving spends: %w", err)
+		}
+
+		if err := s.spendOutput(ctx, int(spend.Height), input); err != nil {
+			return fmt.Errorf("resolving spends: %w", err)
+		}
+	}
+
+	return nil
+}
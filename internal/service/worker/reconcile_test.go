@@ -0,0 +1,195 @@
+package worker
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/golang-migrate/migrate/v4/database/spanner"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/alicenet/alicenet/proto"
+	"github.com/alicenet/utilities/internal/alicenet"
+	"github.com/alicenet/utilities/internal/migrations"
+	"github.com/alicenet/utilities/internal/mocks"
+	"github.com/alicenet/utilities/internal/store"
+)
+
+// minedTransaction decoded from its JSON representation.
+func minedTransaction(t *testing.T, raw string) *proto.MinedTransactionResponse {
+	t.Helper()
+
+	var txn proto.MinedTransactionResponse
+	if err := protojson.Unmarshal([]byte(raw), &txn); err != nil {
+		t.Fatal(err)
+	}
+
+	return &txn
+}
+
+// sqliteStores in a temporary database with all migrations applied.
+func sqliteStores(t *testing.T) *alicenet.Stores {
+	t.Helper()
+
+	db, err := store.OpenSQLite(filepath.Join(t.TempDir(), "indexer.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { db.Close() })
+
+	if err := migrations.RunSQLiteMigrations(db); err != nil {
+		t.Fatal(err)
+	}
+
+	return alicenet.InSQLite(db)
+}
+
+func TestReconcile(t *testing.T) {
+	t.Parallel()
+
+	t.Run("memory", func(t *testing.T) {
+		t.Parallel()
+		testReconcile(t, alicenet.InMemory())
+	})
+
+	t.Run("sqlite", func(t *testing.T) {
+		t.Parallel()
+		testReconcile(t, sqliteStores(t))
+	})
+}
+
+func testReconcile(t *testing.T, stores *alicenet.Stores) {
+	t.Helper()
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	client := mocks.NewMockInterface(ctrl)
+
+	s := New(client, stores, WithReconcilePolicy(ReconcilePolicy{
+		Interval:    time.Second,
+		MaxAttempts: 5,
+		BatchSize:   10,
+	}))
+
+	// Transaction "b" spends the only output of "a", which can't be fetched when the block is indexed.
+	spender := minedTransaction(t, `{"Tx": {"Vin": [
+		{"TXInLinker": {"TxHash": "b", "TXInPreImage": {"ConsumedTxHash": "a", "ConsumedTxIdx": 0}}}
+	]}}`)
+	recovered := minedTransaction(t, `{"Tx": {"Vout": [
+		{"ValueStore": {"TxHash": "a", "VSPreImage": {"Owner": "owner", "Value": "ff", "TXOutIdx": 0}}}
+	]}}`)
+
+	block := &fetchedBlock{
+		height: 1,
+		header: &proto.BlockHeader{BClaims: &proto.BClaims{Height: 1}, TxHshLst: []string{"a", "b"}},
+		transactions: []fetchedTransaction{
+			{hash: "a", err: errUnavailable},
+			{hash: "b", transaction: spender},
+		},
+	}
+
+	if err := s.commit(ctx, block); err != nil {
+		t.Fatal(err)
+	}
+
+	gomock.InOrder(
+		client.EXPECT().Transaction(gomock.Any(), "a").Return(nil, errUnavailable),
+		client.EXPECT().Transaction(gomock.Any(), "a").Return(recovered, nil),
+	)
+
+	// The final pass finds nothing left to retry, so makes no requests.
+	for i := 0; i < 3; i++ {
+		if err := s.reconcileOnce(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	txn, err := stores.Transactions.Get(ctx, store.Key{"a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if txn.Missing != nil && *txn.Missing {
+		t.Error("transaction still missing after being reconciled")
+	}
+
+	output, err := stores.ValueStores.Get(ctx, store.Key{"a", int64(0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if output.SpentTransactionHash == nil || *output.SpentTransactionHash != "b" {
+		t.Errorf("output spent by, want: %s, got: %v", "b", output.SpentTransactionHash)
+	}
+
+	account, err := stores.Accounts.Get(ctx, store.Key{"owner"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if account.Balance != "0" {
+		t.Errorf("balance, want: %s, got: %s", "0", account.Balance)
+	}
+
+	resolved, err := stores.MissingTransactions.Get(ctx, store.Key{"a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resolved.Resolved == nil || !*resolved.Resolved || resolved.Attempts != 3 {
+		t.Errorf("reconciled record, want resolved after 3 attempts, got: %+v", resolved)
+	}
+}
+
+//nolint:paralleltest // t.Parallel not supported with t.Setenv
+func TestReconcileAbandons(t *testing.T) {
+	migrations.SetupEmulator(t)
+	migrations.RunTestMigrations(t)
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	client := mocks.NewMockInterface(ctrl)
+	stores := alicenet.InSpanner(migrations.EmulatorClient(t))
+
+	s := New(client, stores, WithReconcilePolicy(ReconcilePolicy{
+		Interval:    time.Second,
+		MaxAttempts: 3,
+		BatchSize:   10,
+	}))
+
+	missing := alicenet.MissingTransaction{
+		TransactionHash: "a",
+		Height:          1,
+		Attempts:        1,
+		LastError:       "purged",
+		ObserveTime:     store.CommitTimestamp,
+	}
+	if err := stores.MissingTransactions.Insert(ctx, missing); err != nil {
+		t.Fatal(err)
+	}
+
+	client.EXPECT().Transaction(gomock.Any(), "a").Return(nil, errUnavailable).Times(2)
+
+	// The final pass finds nothing left to retry, so makes no requests.
+	for i := 0; i < 3; i++ {
+		if err := s.reconcileOnce(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := stores.MissingTransactions.Get(ctx, store.Key{"a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Abandoned == nil || !*got.Abandoned {
+		t.Error("transaction not abandoned")
+	}
+
+	if got.Attempts != 3 || got.LastError != errUnavailable.Error() {
+		t.Errorf("attempts and last error, want: 3 %s, got: %d %s", errUnavailable, got.Attempts, got.LastError)
+	}
+}
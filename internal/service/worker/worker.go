@@ -3,18 +3,19 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"sync"
 	"time"
 
-	"cloud.google.com/go/spanner"
 	"go.opencensus.io/stats"
 	"go.opencensus.io/stats/view"
 
 	"github.com/alicenet/alicenet/proto"
 	"github.com/alicenet/utilities/internal/alicenet"
 	"github.com/alicenet/utilities/internal/logz"
+	"github.com/alicenet/utilities/internal/store"
 )
 
 // Wait time between checks against alicenet.
@@ -23,12 +24,20 @@ const (
 	baseHex  = 16
 )
 
+// DefaultFetchConcurrency is the number of concurrent requests made to alicenet when none is configured.
+const DefaultFetchConcurrency = 4
+
+// checkpointName identifies the checkpoint row tracking indexed blocks.
+const checkpointName = "blocks"
+
 var (
 	//nolint:gochecknoglobals // Stats exempt
 	highestBlock = stats.Int64("highest_block", "The highest seen block", "1")
 	//nolint:gochecknoglobals // Stats exempt
 	currentBlock = stats.Int64("current_block", "The current processed block", "1")
 	//nolint:gochecknoglobals // Stats exempt
+	negativeBalances = stats.Int64("negative_balances", "Account updates that left a balance below zero", "1")
+	//nolint:gochecknoglobals // Stats exempt
 	views = []*view.View{
 		{
 			Name:        "highest_block_last",
@@ -48,6 +57,24 @@ var (
 			Description: "The number of blocks processed",
 			Aggregation: view.Count(),
 		},
+		{
+			Name:        "chain_discontinuities_count",
+			Measure:     chainDiscontinuities,
+			Description: "The number of blocks that did not link to the indexed chain",
+			Aggregation: view.Count(),
+		},
+		{
+			Name:        "negative_balances_count",
+			Measure:     negativeBalances,
+			Description: "The number of account updates that left a balance below zero",
+			Aggregation: view.Count(),
+		},
+		{
+			Name:        "reconciled_transactions_count",
+			Measure:     reconciledTransactions,
+			Description: "The number of missing transactions recovered",
+			Aggregation: view.Count(),
+		},
 	}
 	//nolint:gochecknoglobals // Stats exempt
 	setupStats sync.Once
@@ -63,13 +90,38 @@ func (p ParseError) Error() string {
 
 // A Service that will periodically check alicenet for latest blocks and add them to the index.
 type Service struct {
-	stores  *alicenet.Stores
-	client  alicenet.Interface
-	highest int
+	stores       *alicenet.Stores
+	client       alicenet.Interface
+	highest      int
+	previousHash string
+	startHeight  int
+	resumed      bool
+	concurrency  int
+	reconcile    ReconcilePolicy
+}
+
+// An Option to configure the Service.
+type Option func(*Service)
+
+// WithStartHeight begins indexing at the given height when it is above the stored checkpoint. Lower heights are
+// ignored, so blocks are never indexed twice and the option can stay configured across restarts.
+func WithStartHeight(height int) Option {
+	return func(s *Service) {
+		s.startHeight = height
+	}
+}
+
+// WithFetchConcurrency bounds the number of concurrent requests made to alicenet while prefetching blocks.
+func WithFetchConcurrency(concurrency int) Option {
+	return func(s *Service) {
+		if concurrency > 0 {
+			s.concurrency = concurrency
+		}
+	}
 }
 
 // New Service from an alicenet client and stores.
-func New(client alicenet.Interface, stores *alicenet.Stores) *Service {
+func New(client alicenet.Interface, stores *alicenet.Stores, opts ...Option) *Service {
 	setupStats.Do(func() {
 		for i := range views {
 			if err := view.Register(views[i]); err != nil {
@@ -78,17 +130,35 @@ func New(client alicenet.Interface, stores *alicenet.Stores) *Service {
 		}
 	})
 
-	return &Service{
-		client:  client,
-		stores:  stores,
-		highest: 1,
+	s := &Service{
+		client:      client,
+		stores:      stores,
+		concurrency: DefaultFetchConcurrency,
+		reconcile:   DefaultReconcilePolicy,
+	}
+
+	for _, opt := range opts {
+		opt(s)
 	}
+
+	return s
 }
 
-// Run the service.
+// Run the service. Indexing halts if alicenet serves a block that does not link to the indexed chain.
 func (s *Service) Run(ctx context.Context) {
+	if s.reconcile.Interval > 0 {
+		go s.reconcileLoop(ctx)
+	}
+
 	for {
 		if err := s.process(ctx); err != nil {
+			var discontinuity *DiscontinuityError
+			if errors.As(err, &discontinuity) {
+				logz.WithDetail("err", err).Criticalf("halting: %v", err)
+
+				return
+			}
+
 			logz.WithDetail("err", err).Errorf("run error: %v", err)
 		}
 
@@ -100,8 +170,63 @@ func (s *Service) Run(ctx context.Context) {
 	}
 }
 
+// resume after the stored checkpoint, or from the start height if one was configured above it. Blocks indexed before
+// checkpoints were written are checkpointed first, so they aren't indexed again.
+func (s *Service) resume(ctx context.Context) error {
+	var indexed int
+
+	checkpoint, err := s.stores.Checkpoints.Get(ctx, store.Key{checkpointName})
+
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		blocks, err := s.stores.Blocks.List(ctx, nil, 1, 0)
+		if err != nil {
+			return fmt.Errorf("resuming: %w", err)
+		}
+
+		if len(blocks) > 0 {
+			indexed = int(blocks[0].Height)
+
+			logz.WithDetail("height", indexed).Notice("checkpointing blocks indexed without one")
+
+			if err := s.pushCheckpoint(ctx, indexed); err != nil {
+				return fmt.Errorf("resuming: %w", err)
+			}
+		}
+	case err != nil:
+		return fmt.Errorf("resuming: %w", err)
+	default:
+		indexed = int(checkpoint.Height)
+	}
+
+	switch {
+	case s.startHeight > indexed+1:
+		logz.WithDetail("height", s.startHeight).Notice("starting from configured height")
+
+		s.highest = s.startHeight - 1
+	case indexed > 0:
+		logz.WithDetail("height", indexed).Notice("resuming from checkpoint")
+
+		s.highest = indexed
+	default:
+		logz.Notice("no checkpoint found, starting from genesis")
+
+		s.highest = 0
+	}
+
+	s.resumed = true
+
+	return nil
+}
+
 // process any new blocks found in alicenet.
 func (s *Service) process(ctx context.Context) error {
+	if !s.resumed {
+		if err := s.resume(ctx); err != nil {
+			return fmt.Errorf("processing: %w", err)
+		}
+	}
+
 	current, err := s.client.Height(ctx)
 	if err != nil {
 		return fmt.Errorf("processing: %w", err)
@@ -110,37 +235,72 @@ func (s *Service) process(ctx context.Context) error {
 	logz.WithDetails(logz.Details{"current": current, "highest": s.highest}).Info()
 	stats.Record(ctx, highestBlock.M(int64(current)))
 
-	for height := s.highest; height <= int(current); height++ {
-		stats.Record(ctx, currentBlock.M(int64(height)))
+	// Stop any prefetching still in flight if a block fails to commit.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-		blockHeader, err := s.client.BlockHeader(ctx, uint32(height))
-		if err != nil {
+	for result := range s.prefetch(ctx, s.highest+1, int(current)) {
+		if result.err != nil {
+			return fmt.Errorf("processing: %w", result.err)
+		}
+
+		stats.Record(ctx, currentBlock.M(int64(result.block.height)))
+
+		if err := s.verify(ctx, result.block); err != nil {
 			return fmt.Errorf("processing: %w", err)
 		}
 
-		if err := s.pushBlock(ctx, blockHeader); err != nil {
-			return err
+		if err := s.commit(ctx, result.block); err != nil {
+			return fmt.Errorf("processing: %w", err)
 		}
 
-		for _, hash := range blockHeader.TxHshLst {
-			txn, err := s.client.Transaction(ctx, hash)
-			if err != nil {
-				// Transaction has likely been purged from the chain. Mark it as missing and continue.
-				logz.WithDetail("hash", hash).Warning("transaction missing, continuing")
+		s.highest = result.block.height
+		s.previousHash = result.block.header.BClaims.HeaderRoot
+	}
+
+	return nil
+}
 
-				if err := s.pushMissingTransaction(ctx, height, hash); err != nil {
+// commit a block, its transactions and the checkpoint for its height as a single atomic write.
+func (s *Service) commit(ctx context.Context, block *fetchedBlock) error {
+	err := s.stores.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.pushBlock(ctx, block.header); err != nil {
+			return err
+		}
+
+		for _, txn := range block.transactions {
+			if txn.transaction == nil {
+				if err := s.pushMissingTransaction(ctx, block.height, txn.hash, txn.err); err != nil {
 					return err
 				}
 
 				continue
 			}
 
-			if err := s.pushTransaction(ctx, height, hash, txn); err != nil {
+			if err := s.pushTransaction(ctx, block.height, txn.hash, txn.transaction); err != nil {
 				return err
 			}
 		}
 
-		s.highest = height
+		return s.pushCheckpoint(ctx, block.height)
+	})
+	if err != nil {
+		return fmt.Errorf("committing: %w", err)
+	}
+
+	return nil
+}
+
+// pushCheckpoint to the permanent stores alongside every other write for a height.
+func (s *Service) pushCheckpoint(ctx context.Context, height int) error {
+	checkpoint := alicenet.Checkpoint{
+		Name:        checkpointName,
+		Height:      int64(height),
+		ObserveTime: store.CommitTimestamp,
+	}
+
+	if err := s.stores.Checkpoints.Insert(ctx, checkpoint); err != nil {
+		return fmt.Errorf("pushing checkpoint: %w", err)
 	}
 
 	return nil
@@ -171,7 +331,7 @@ func createBlock(blockHeader *proto.BlockHeader) alicenet.Block {
 		HeaderRootHash:      blockHeader.BClaims.HeaderRoot,
 		GroupSignatureHash:  blockHeader.SigGroup,
 		TransactionHashes:   blockHeader.TxHshLst,
-		ObserveTime:         spanner.CommitTimestamp,
+		ObserveTime:         store.CommitTimestamp,
 	}
 
 	return block
@@ -182,21 +342,21 @@ func (s *Service) pushTransaction(
 	ctx context.Context,
 	height int,
 	hash string,
-	txn *alicenet.MinedTransactionResponse,
+	txn *proto.MinedTransactionResponse,
 ) error {
 	logz.WithDetail("transaction", txn).Info("got transaction")
 
 	newTx := alicenet.Transaction{
 		Height:          int64(height),
 		TransactionHash: hash,
-		ObserveTime:     spanner.CommitTimestamp,
+		ObserveTime:     store.CommitTimestamp,
 	}
 
 	if err := s.stores.Transactions.Insert(ctx, newTx); err != nil {
 		return fmt.Errorf("pushing transaction: %w", err)
 	}
 
-	if err := s.pushTransactionInput(ctx, txn); err != nil {
+	if err := s.pushTransactionInput(ctx, height, txn); err != nil {
 		return fmt.Errorf("pushing transaction: %w", err)
 	}
 
@@ -207,11 +367,12 @@ func (s *Service) pushTransaction(
 	return nil
 }
 
-// pushTransaction to the permanent stores.
+// pushMissingTransaction to the permanent stores, queueing it to be reconciled.
 func (s *Service) pushMissingTransaction(
 	ctx context.Context,
 	height int,
 	hash string,
+	fetchErr error,
 ) error {
 	logz.WithDetail("hash", hash).Info("writing missing transaction")
 
@@ -220,7 +381,7 @@ func (s *Service) pushMissingTransaction(
 	newTx := alicenet.Transaction{
 		Height:          int64(height),
 		TransactionHash: hash,
-		ObserveTime:     spanner.CommitTimestamp,
+		ObserveTime:     store.CommitTimestamp,
 		Missing:         &missing,
 	}
 
@@ -228,67 +389,82 @@ func (s *Service) pushMissingTransaction(
 		return fmt.Errorf("pushing transaction: %w", err)
 	}
 
+	lastError := ""
+	if fetchErr != nil {
+		lastError = fetchErr.Error()
+	}
+
+	missingTx := alicenet.MissingTransaction{
+		TransactionHash: hash,
+		Height:          int64(height),
+		Attempts:        1,
+		LastError:       lastError,
+		ObserveTime:     store.CommitTimestamp,
+	}
+
+	if err := s.stores.MissingTransactions.Insert(ctx, missingTx); err != nil {
+		return fmt.Errorf("pushing transaction: %w", err)
+	}
+
 	return nil
 }
 
 // pushTransactionOutput to permanent stores.
-func (s *Service) pushTransactionOutput(ctx context.Context, txn *alicenet.MinedTransactionResponse) error {
-	for _, vout := range txn.Tx.Vout {
-		switch {
-		case vout.DataStore != nil:
+func (s *Service) pushTransactionOutput(ctx context.Context, txn *proto.MinedTransactionResponse) error {
+	for _, vout := range txn.GetTx().GetVout() {
+		// Other outputs, such as atomic swaps, hold neither store and aren't indexed.
+		if ds := vout.GetDataStore(); ds != nil {
+			preImage := ds.GetDSLinker().GetDSPreImage()
 			output := alicenet.DataStore{
-				Signature:           vout.DataStore.Signature,
-				TransactionHash:     vout.DataStore.DSLinker.TxHash,
-				ChainID:             int64(vout.DataStore.DSLinker.DSPreImage.ChainID),
-				Index:               vout.DataStore.DSLinker.DSPreImage.Index,
-				IssuedAt:            int64(vout.DataStore.DSLinker.DSPreImage.IssuedAt),
-				Deposit:             vout.DataStore.DSLinker.DSPreImage.Deposit,
-				RawData:             vout.DataStore.DSLinker.DSPreImage.RawData,
-				TransactionOutIndex: int64(vout.DataStore.DSLinker.DSPreImage.TXOutIdx),
-				Owner:               vout.DataStore.DSLinker.DSPreImage.Owner,
-				Fee:                 vout.DataStore.DSLinker.DSPreImage.Fee,
-				ObserveTime:         spanner.CommitTimestamp,
+				Signature:           ds.GetSignature(),
+				TransactionHash:     ds.GetDSLinker().GetTxHash(),
+				ChainID:             int64(preImage.GetChainID()),
+				Index:               preImage.GetIndex(),
+				IssuedAt:            int64(preImage.GetIssuedAt()),
+				Deposit:             preImage.GetDeposit(),
+				RawData:             preImage.GetRawData(),
+				TransactionOutIndex: int64(preImage.GetTXOutIdx()),
+				Owner:               preImage.GetOwner(),
+				Fee:                 preImage.GetFee(),
+				ObserveTime:         store.CommitTimestamp,
 			}
 			if err := s.stores.DataStores.Insert(ctx, output); err != nil {
 				return fmt.Errorf("output: %w", err)
 			}
 
-			if err := s.pushAccount(
-				ctx, vout.DataStore.DSLinker.DSPreImage.Owner,
-				vout.DataStore.DSLinker.TxHash,
-				"0",
-			); err != nil {
+			if err := s.pushAccount(ctx, output.Owner, output.TransactionHash, "0"); err != nil {
 				return fmt.Errorf("output: %w", err)
 			}
 
-			if err := s.pushStoredData(
-				ctx,
-				vout.DataStore.DSLinker.DSPreImage.Owner,
-				vout.DataStore.DSLinker.DSPreImage.Index,
-				int64(vout.DataStore.DSLinker.DSPreImage.IssuedAt),
-				vout.DataStore.DSLinker.DSPreImage.RawData); err != nil {
+			if err := s.pushAccountOutput(ctx, output.Owner, output.TransactionHash, output.TransactionOutIndex); err != nil {
 				return fmt.Errorf("output: %w", err)
 			}
-		case vout.ValueStore != nil:
+
+			if err := s.pushStoredData(ctx, output.Owner, output.Index, output.IssuedAt, output.RawData); err != nil {
+				return fmt.Errorf("output: %w", err)
+			}
+		}
+
+		if vs := vout.GetValueStore(); vs != nil {
+			preImage := vs.GetVSPreImage()
 			output := alicenet.ValueStore{
-				TransactionHash:     vout.ValueStore.TxHash,
-				ChainID:             int64(vout.ValueStore.VSPreImage.ChainID),
-				Value:               vout.ValueStore.VSPreImage.Value,
-				TransactionOutIndex: int64(vout.ValueStore.VSPreImage.TXOutIdx),
-				Owner:               vout.ValueStore.VSPreImage.Owner,
-				Fee:                 vout.ValueStore.VSPreImage.Fee,
-				ObserveTime:         spanner.CommitTimestamp,
+				TransactionHash:     vs.GetTxHash(),
+				ChainID:             int64(preImage.GetChainID()),
+				Value:               preImage.GetValue(),
+				TransactionOutIndex: int64(preImage.GetTXOutIdx()),
+				Owner:               preImage.GetOwner(),
+				Fee:                 preImage.GetFee(),
+				ObserveTime:         store.CommitTimestamp,
 			}
 			if err := s.stores.ValueStores.Insert(ctx, output); err != nil {
 				return fmt.Errorf("output: %w", err)
 			}
 
-			if err := s.pushAccount(
-				ctx,
-				vout.ValueStore.VSPreImage.Owner,
-				vout.ValueStore.TxHash,
-				vout.ValueStore.VSPreImage.Value,
-			); err != nil {
+			if err := s.pushAccount(ctx, output.Owner, output.TransactionHash, output.Value); err != nil {
+				return fmt.Errorf("output: %w", err)
+			}
+
+			if err := s.pushAccountOutput(ctx, output.Owner, output.TransactionHash, output.TransactionOutIndex); err != nil {
 				return fmt.Errorf("output: %w", err)
 			}
 		}
@@ -297,14 +473,32 @@ func (s *Service) pushTransactionOutput(ctx context.Context, txn *alicenet.Mined
 	return nil
 }
 
-// pushAccount to permanent stores. Will associate transaction and amount stored.
+// pushAccount to permanent stores. Will associate transaction and credit the amount stored.
 func (s *Service) pushAccount(ctx context.Context, owner, hash, amount string) error {
-	account, err := s.stores.Accounts.Get(ctx, spanner.Key{owner})
-	if err != nil {
+	return s.updateAccount(ctx, owner, hash, amount, (*big.Int).Add)
+}
+
+// pullAccount from permanent stores. Will associate transaction and debit the amount spent.
+func (s *Service) pullAccount(ctx context.Context, owner, hash, amount string) error {
+	return s.updateAccount(ctx, owner, hash, amount, (*big.Int).Sub)
+}
+
+// updateAccount balance by applying op to the current balance and amount, then associate the transaction.
+func (s *Service) updateAccount(
+	ctx context.Context,
+	owner, hash, amount string,
+	op func(z, x, y *big.Int) *big.Int,
+) error {
+	account, err := s.stores.Accounts.Get(ctx, store.Key{owner})
+
+	switch {
+	case errors.Is(err, store.ErrNotFound):
 		account = alicenet.Account{
 			Address: owner,
 			Balance: "0",
 		}
+	case err != nil:
+		return fmt.Errorf("account: %w", err)
 	}
 
 	current, success := new(big.Int).SetString(account.Balance, baseHex)
@@ -312,12 +506,20 @@ func (s *Service) pushAccount(ctx context.Context, owner, hash, amount string) e
 		return ParseError(account.Balance)
 	}
 
-	added, success := new(big.Int).SetString(amount, baseHex)
+	delta, success := new(big.Int).SetString(amount, baseHex)
 	if !success {
 		return ParseError(amount)
 	}
 
-	total := new(big.Int).Add(current, added)
+	// Spends are only debited once the output they consume has been credited, so a negative balance means the index
+	// is inconsistent. It is kept rather than clamped, so that it is exactly right again if the missing credit arrives.
+	total := op(new(big.Int), current, delta)
+	if total.Sign() < 0 {
+		stats.Record(ctx, negativeBalances.M(1))
+		logz.WithDetails(logz.Details{"address": owner, "balance": total.Text(baseHex)}).
+			Alert("balance below zero")
+	}
+
 	account.Balance = total.Text(baseHex)
 
 	if err := s.stores.Accounts.Insert(ctx, account); err != nil {
@@ -327,7 +529,7 @@ func (s *Service) pushAccount(ctx context.Context, owner, hash, amount string) e
 	txn := alicenet.AccountTransaction{
 		Address:         owner,
 		TransactionHash: hash,
-		ObserveTime:     spanner.CommitTimestamp,
+		ObserveTime:     store.CommitTimestamp,
 	}
 
 	if err := s.stores.AccountTransactions.Insert(ctx, txn); err != nil {
@@ -337,6 +539,22 @@ func (s *Service) pushAccount(ctx context.Context, owner, hash, amount string) e
 	return nil
 }
 
+// pushAccountOutput to permanent stores so the output can be listed as unspent for its owner.
+func (s *Service) pushAccountOutput(ctx context.Context, owner, hash string, index int64) error {
+	output := alicenet.AccountOutput{
+		Address:             owner,
+		TransactionHash:     hash,
+		TransactionOutIndex: index,
+		ObserveTime:         store.CommitTimestamp,
+	}
+
+	if err := s.stores.AccountOutputs.Insert(ctx, output); err != nil {
+		return fmt.Errorf("account output: %w", err)
+	}
+
+	return nil
+}
+
 // pushStoredData to permanent stores.
 func (s *Service) pushStoredData(ctx context.Context, owner, index string, issuedAt int64, value string) error {
 	accountStore := alicenet.AccountStore{
@@ -344,7 +562,7 @@ func (s *Service) pushStoredData(ctx context.Context, owner, index string, issue
 		Index:       index,
 		IssuedAt:    issuedAt,
 		Value:       value,
-		ObserveTime: spanner.CommitTimestamp,
+		ObserveTime: store.CommitTimestamp,
 	}
 
 	if err := s.stores.AccountStores.Insert(ctx, accountStore); err != nil {
@@ -355,21 +573,138 @@ func (s *Service) pushStoredData(ctx context.Context, owner, index string, issue
 }
 
 // pushTransactionInput to the permanent stores.
-func (s *Service) pushTransactionInput(ctx context.Context, txn *alicenet.MinedTransactionResponse) error {
-	for index, input := range txn.Tx.Vin {
+func (s *Service) pushTransactionInput(ctx context.Context, height int, txn *proto.MinedTransactionResponse) error {
+	for index, vin := range txn.GetTx().GetVin() {
+		preImage := vin.GetTXInLinker().GetTXInPreImage()
 		input := alicenet.TransactionInput{
-			TransactionHash:          input.TXInLinker.TxHash,
+			TransactionHash:          vin.GetTXInLinker().GetTxHash(),
 			TransactionIndex:         int64(index),
-			ChainID:                  int64(input.TXInLinker.TXInPreImage.ChainID),
-			ConsumedTransactionHash:  input.TXInLinker.TXInPreImage.ConsumedTxHash,
-			ConsumedTransactionIndex: int64(input.TXInLinker.TXInPreImage.ConsumedTxIdx),
-			Signature:                input.Signature,
-			ObserveTime:              spanner.CommitTimestamp,
+			ChainID:                  int64(preImage.GetChainID()),
+			ConsumedTransactionHash:  preImage.GetConsumedTxHash(),
+			ConsumedTransactionIndex: int64(preImage.GetConsumedTxIdx()),
+			Signature:                vin.GetSignature(),
+			ObserveTime:              store.CommitTimestamp,
 		}
 
 		if err := s.stores.TransactionInputs.Insert(ctx, input); err != nil {
 			return fmt.Errorf("input: %w", err)
 		}
+
+		if err := s.spendOutput(ctx, height, input); err != nil {
+			return fmt.Errorf("input: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// spendOutput consumed by an input. Records the spending input on the output and debits the previous owner.
+func (s *Service) spendOutput(ctx context.Context, height int, input alicenet.TransactionInput) error {
+	key := store.Key{input.ConsumedTransactionHash, input.ConsumedTransactionIndex}
+	spent := true
+	spentHeight := int64(height)
+
+	valueStore, err := s.stores.ValueStores.Get(ctx, key)
+
+	switch {
+	case err == nil:
+		if valueStore.Spent != nil && *valueStore.Spent {
+			return nil
+		}
+
+		valueStore.Spent = &spent
+		valueStore.SpentTransactionHash = &input.TransactionHash
+		valueStore.SpentInputIndex = &input.TransactionIndex
+		valueStore.SpentHeight = &spentHeight
+
+		if err := s.stores.ValueStores.Insert(ctx, valueStore); err != nil {
+			return fmt.Errorf("spend: %w", err)
+		}
+
+		if err := s.pullAccountOutput(
+			ctx,
+			valueStore.Owner,
+			valueStore.TransactionHash,
+			valueStore.TransactionOutIndex,
+		); err != nil {
+			return fmt.Errorf("spend: %w", err)
+		}
+
+		return s.pullAccount(ctx, valueStore.Owner, input.TransactionHash, valueStore.Value)
+	case !errors.Is(err, store.ErrNotFound):
+		return fmt.Errorf("spend: %w", err)
+	}
+
+	dataStore, err := s.stores.DataStores.Get(ctx, key)
+
+	switch {
+	case err == nil:
+		if dataStore.Spent != nil && *dataStore.Spent {
+			return nil
+		}
+
+		dataStore.Spent = &spent
+		dataStore.SpentTransactionHash = &input.TransactionHash
+		dataStore.SpentInputIndex = &input.TransactionIndex
+		dataStore.SpentHeight = &spentHeight
+
+		if err := s.stores.DataStores.Insert(ctx, dataStore); err != nil {
+			return fmt.Errorf("spend: %w", err)
+		}
+
+		if err := s.pullAccountOutput(
+			ctx,
+			dataStore.Owner,
+			dataStore.TransactionHash,
+			dataStore.TransactionOutIndex,
+		); err != nil {
+			return fmt.Errorf("spend: %w", err)
+		}
+
+		// DataStores never credit their owner, so consuming them only associates the transaction.
+		return s.pullAccount(ctx, dataStore.Owner, input.TransactionHash, "0")
+	case !errors.Is(err, store.ErrNotFound):
+		return fmt.Errorf("spend: %w", err)
+	}
+
+	// The consumed output belongs to a transaction that could not be indexed. Remember the spend so the debit can
+	// be applied if the transaction is reconciled.
+	logz.WithDetails(logz.Details{
+		"hash":          input.TransactionHash,
+		"consumedHash":  input.ConsumedTransactionHash,
+		"consumedIndex": input.ConsumedTransactionIndex,
+	}).Warning("consumed output not found, deferring debit")
+
+	unresolved := alicenet.UnresolvedSpend{
+		ConsumedTransactionHash:  input.ConsumedTransactionHash,
+		ConsumedTransactionIndex: input.ConsumedTransactionIndex,
+		TransactionHash:          input.TransactionHash,
+		TransactionIndex:         input.TransactionIndex,
+		Height:                   spentHeight,
+		ObserveTime:              store.CommitTimestamp,
+	}
+
+	if err := s.stores.UnresolvedSpends.Insert(ctx, unresolved); err != nil {
+		return fmt.Errorf("spend: %w", err)
+	}
+
+	return nil
+}
+
+// pullAccountOutput marks an output as spent so it is no longer listed as unspent for its owner.
+func (s *Service) pullAccountOutput(ctx context.Context, owner, hash string, index int64) error {
+	spent := true
+
+	output := alicenet.AccountOutput{
+		Address:             owner,
+		TransactionHash:     hash,
+		TransactionOutIndex: index,
+		Spent:               &spent,
+		ObserveTime:         store.CommitTimestamp,
+	}
+
+	if err := s.stores.AccountOutputs.Insert(ctx, output); err != nil {
+		return fmt.Errorf("account output: %w", err)
 	}
 
 	return nil
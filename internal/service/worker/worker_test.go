@@ -0,0 +1,249 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/alicenet/alicenet/proto"
+	"github.com/alicenet/utilities/internal/alicenet"
+	"github.com/alicenet/utilities/internal/mocks"
+	"github.com/alicenet/utilities/internal/store"
+)
+
+// unavailable store failing every Get.
+type unavailable[T store.Storable] struct {
+	store.Store[T]
+}
+
+func (unavailable[T]) Get(context.Context, store.Key) (T, error) {
+	var item T
+
+	return item, store.ErrUnavailable
+}
+
+func TestUpdateAccount(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	stores := alicenet.InMemory()
+	s := New(nil, stores)
+
+	for _, step := range []struct {
+		update func(ctx context.Context, owner, hash, amount string) error
+		amount string
+		want   string
+	}{
+		{s.pushAccount, "10", "10"},
+		{s.pullAccount, "4", "c"},
+		// Debits past zero are kept, so the balance is right once the missing credit is indexed.
+		{s.pullAccount, "ff", "-f3"},
+		{s.pushAccount, "f3", "0"},
+		{s.pushAccount, "1", "1"},
+	} {
+		if err := step.update(ctx, "owner", "hash", step.amount); err != nil {
+			t.Fatal(err)
+		}
+
+		account, err := stores.Accounts.Get(ctx, store.Key{"owner"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if account.Balance != step.want {
+			t.Errorf("balance after %s, want: %s, got: %s", step.amount, step.want, account.Balance)
+		}
+	}
+
+	if _, err := stores.AccountTransactions.Get(ctx, store.Key{"owner", "hash"}); err != nil {
+		t.Errorf("account transaction: %v", err)
+	}
+
+	// Balances are only started from zero for new accounts, never when they can't be read.
+	stores.Accounts = unavailable[alicenet.Account]{stores.Accounts}
+
+	if err := s.pushAccount(ctx, "owner", "hash", "1"); !errors.Is(err, store.ErrUnavailable) {
+		t.Errorf("unreadable account, want: %v, got: %v", store.ErrUnavailable, err)
+	}
+}
+
+func TestResume(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]struct {
+		checkpoint  int64
+		block       int64
+		startHeight int
+		want        int
+	}{
+		"genesis":                       {want: 0},
+		"checkpoint":                    {checkpoint: 5, block: 5, want: 5},
+		"start above checkpoint":        {checkpoint: 5, block: 5, startHeight: 10, want: 9},
+		"start after checkpoint":        {checkpoint: 5, block: 5, startHeight: 6, want: 5},
+		"start at checkpoint":           {checkpoint: 5, block: 5, startHeight: 5, want: 5},
+		"start below checkpoint":        {checkpoint: 5, block: 5, startHeight: 2, want: 5},
+		"start on empty store":          {startHeight: 10, want: 9},
+		"blocks without checkpoint":     {block: 3, want: 3},
+		"start within unchecked blocks": {block: 3, startHeight: 3, want: 3},
+		"start above unchecked blocks":  {block: 3, startHeight: 8, want: 7},
+	} {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			stores := alicenet.InMemory()
+
+			if test.block > 0 {
+				if err := stores.Blocks.Insert(ctx, alicenet.Block{Height: test.block}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if test.checkpoint > 0 {
+				checkpoint := alicenet.Checkpoint{Name: checkpointName, Height: test.checkpoint}
+				if err := stores.Checkpoints.Insert(ctx, checkpoint); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			s := New(nil, stores, WithStartHeight(test.startHeight))
+			if err := s.resume(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			if s.highest != test.want {
+				t.Errorf("highest, want: %d, got: %d", test.want, s.highest)
+			}
+
+			// Blocks indexed without a checkpoint are checkpointed, so later restarts resume after them.
+			if test.checkpoint == 0 && test.block > 0 {
+				checkpoint, err := stores.Checkpoints.Get(ctx, store.Key{checkpointName})
+				if err != nil || checkpoint.Height != test.block {
+					t.Errorf("checkpoint, want: %d, got: %d %v", test.block, checkpoint.Height, err)
+				}
+			}
+		})
+	}
+}
+
+func TestRestartWithStartHeight(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	stores := alicenet.InMemory()
+	ctrl := gomock.NewController(t)
+	client := mocks.NewMockInterface(ctrl)
+
+	// Each height is fetched once, across both runs.
+	for height := uint32(3); height <= 7; height++ {
+		client.EXPECT().BlockHeader(gomock.Any(), height).Return(&proto.BlockHeader{
+			BClaims: &proto.BClaims{
+				Height:     height,
+				HeaderRoot: fmt.Sprintf("h%d", height),
+				PrevBlock:  fmt.Sprintf("h%d", height-1),
+			},
+		}, nil)
+	}
+
+	gomock.InOrder(
+		client.EXPECT().Height(gomock.Any()).Return(uint32(5), nil),
+		client.EXPECT().Height(gomock.Any()).Return(uint32(7), nil),
+	)
+
+	if err := New(client, stores, WithStartHeight(3)).process(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Restarting with the same start height resumes after the blocks already indexed.
+	restarted := New(client, stores, WithStartHeight(3))
+	if err := restarted.process(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if restarted.highest != 7 {
+		t.Errorf("highest, want: %d, got: %d", 7, restarted.highest)
+	}
+}
+
+func TestSpendOutput(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	stores := alicenet.InMemory()
+	s := New(nil, stores)
+
+	for _, err := range []error{
+		stores.ValueStores.Insert(ctx, alicenet.ValueStore{TransactionHash: "a", Owner: "owner", Value: "10"}),
+		stores.DataStores.Insert(ctx, alicenet.DataStore{TransactionHash: "a", TransactionOutIndex: 1, Owner: "owner"}),
+		stores.ValueStores.Insert(ctx, alicenet.ValueStore{
+			TransactionHash: "a", TransactionOutIndex: 2, Owner: "owner", Value: "5",
+		}),
+		s.pushAccount(ctx, "owner", "a", "15"),
+		s.pushAccountOutput(ctx, "owner", "a", 0),
+		s.pushAccountOutput(ctx, "owner", "a", 1),
+		s.pushAccountOutput(ctx, "owner", "a", 2),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, input := range []alicenet.TransactionInput{
+		{TransactionHash: "b", ConsumedTransactionHash: "a", ConsumedTransactionIndex: 0},
+		{TransactionHash: "b", TransactionIndex: 1, ConsumedTransactionHash: "a", ConsumedTransactionIndex: 1},
+		// Spending an output again doesn't debit its owner twice.
+		{TransactionHash: "c", ConsumedTransactionHash: "a", ConsumedTransactionIndex: 0},
+		{TransactionHash: "c", TransactionIndex: 1, ConsumedTransactionHash: "x", ConsumedTransactionIndex: 3},
+	} {
+		if err := s.spendOutput(ctx, 2, input); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	valueStore, err := stores.ValueStores.Get(ctx, store.Key{"a", int64(0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if valueStore.Spent == nil || !*valueStore.Spent || *valueStore.SpentTransactionHash != "b" ||
+		*valueStore.SpentInputIndex != 0 || *valueStore.SpentHeight != 2 {
+		t.Errorf("value store, want: spent by b/0 at 2, got: %+v", valueStore)
+	}
+
+	dataStore, err := stores.DataStores.Get(ctx, store.Key{"a", int64(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dataStore.Spent == nil || !*dataStore.Spent || *dataStore.SpentTransactionHash != "b" ||
+		*dataStore.SpentInputIndex != 1 || *dataStore.SpentHeight != 2 {
+		t.Errorf("data store, want: spent by b/1 at 2, got: %+v", dataStore)
+	}
+
+	account, err := stores.Accounts.Get(ctx, store.Key{"owner"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if account.Balance != "5" {
+		t.Errorf("balance, want: 5, got: %s", account.Balance)
+	}
+
+	unspent, err := stores.AccountOutputs.List(ctx, store.Key{"owner"}, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(unspent) != 1 || unspent[0].TransactionOutIndex != 2 {
+		t.Errorf("unspent outputs, want: [a/2], got: %+v", unspent)
+	}
+
+	if _, err := stores.UnresolvedSpends.Get(ctx, store.Key{"x", int64(3)}); err != nil {
+		t.Errorf("unresolved spend: %v", err)
+	}
+}
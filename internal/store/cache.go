@@ -0,0 +1,340 @@
+package store
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"golang.org/x/sync/singleflight"
+)
+
+var (
+	//nolint:gochecknoglobals // Stats exempt
+	cacheHits = stats.Int64("store_cache_hits", "Reads answered from a cache", "1")
+	//nolint:gochecknoglobals // Stats exempt
+	cacheMisses = stats.Int64("store_cache_misses", "Reads passed through a cache to the database", "1")
+	//nolint:gochecknoglobals // Stats exempt
+	tableTag = tag.MustNewKey("table")
+	//nolint:gochecknoglobals // Stats exempt
+	cacheViews = []*view.View{
+		{
+			Name:        "store_cache_hits_count",
+			Measure:     cacheHits,
+			Description: "The number of reads answered from a cache",
+			TagKeys:     []tag.Key{tableTag},
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        "store_cache_misses_count",
+			Measure:     cacheMisses,
+			Description: "The number of reads passed through a cache to the database",
+			TagKeys:     []tag.Key{tableTag},
+			Aggregation: view.Sum(),
+		},
+	}
+	//nolint:gochecknoglobals // Stats exempt
+	setupCacheStats sync.Once
+)
+
+// CacheOptions limit what a Cached store keeps.
+type CacheOptions struct {
+	// Size is the most items kept, evicting the least recently read. Zero keeps every item.
+	Size int
+	// TTL is how long an item is kept after it is read. Zero keeps items until they are evicted.
+	TTL time.Duration
+	// NegativeTTL is how long a key that wasn't found is remembered. Zero doesn't remember them.
+	NegativeTTL time.Duration
+	// ReadTimeout bounds a read from the Store shared by concurrent callers, which carries on if the caller that
+	// started it gives up. Zero uses DefaultCacheReadTimeout.
+	ReadTimeout time.Duration
+}
+
+// DefaultCacheReadTimeout bounds reads shared by concurrent callers when no ReadTimeout is configured.
+const DefaultCacheReadTimeout = 10 * time.Second
+
+// detached carries the values of a context, such as its read staleness, without its deadline or cancellation.
+type detached struct {
+	context.Context //nolint:containedctx // Only its values are used.
+}
+
+// Deadline is never set.
+func (detached) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+// Done is never closed.
+func (detached) Done() <-chan struct{} {
+	return nil
+}
+
+// Err is always nil.
+func (detached) Err() error {
+	return nil
+}
+
+// A cacheEntry for a key, holding the item if it was found.
+type cacheEntry[T Storable] struct {
+	key     Key
+	item    T
+	found   bool
+	expires time.Time
+}
+
+// Cached reads from a Store, for items that don't change once written. Concurrent reads of the same key are made
+// once. Writes through the Cached store invalidate the keys they touch, but writes made elsewhere aren't seen until
+// the cached item expires.
+//
+// Reads in a transaction always go to the Store, so they see the transaction's own writes.
+type Cached[T Storable] struct {
+	Store[T]
+	opts CacheOptions
+	now  func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	recent  *list.List
+	reads   singleflight.Group
+}
+
+// Cache reads from a Store.
+func Cache[T Storable](s Store[T], opts CacheOptions) *Cached[T] {
+	setupCacheStats.Do(func() {
+		if err := view.Register(cacheViews...); err != nil {
+			panic(err)
+		}
+	})
+
+	return &Cached[T]{
+		Store:   s,
+		opts:    opts,
+		now:     time.Now,
+		entries: make(map[string]*list.Element),
+		recent:  list.New(),
+	}
+}
+
+// Insert an item, invalidating any cached copy.
+func (c *Cached[T]) Insert(ctx context.Context, item T) error {
+	err := c.Store.Insert(ctx, item)
+
+	c.invalidate(Prefix(item.Key()))
+
+	return err
+}
+
+// Get an item from the cache, or from the Store if it isn't cached.
+func (c *Cached[T]) Get(ctx context.Context, key Key) (T, error) {
+	var item T
+
+	if inTransaction(ctx) {
+		return c.Store.Get(ctx, key)
+	}
+
+	if entry, ok := c.lookup(key); ok {
+		c.record(ctx, cacheHits, 1)
+
+		if !entry.found {
+			return item, notFound(item.Table(), key)
+		}
+
+		return entry.item, nil
+	}
+
+	c.record(ctx, cacheMisses, 1)
+
+	// The read is shared with any concurrent callers, so it isn't cancelled when this one gives up.
+	reads := c.reads.DoChan(key.String(), func() (any, error) {
+		timeout := c.opts.ReadTimeout
+		if timeout <= 0 {
+			timeout = DefaultCacheReadTimeout
+		}
+
+		ctx, cancel := context.WithTimeout(detached{ctx}, timeout)
+		defer cancel()
+
+		item, err := c.Store.Get(ctx, key)
+
+		switch {
+		case err == nil:
+			c.add(key, item, true)
+		case errors.Is(err, ErrNotFound):
+			c.add(key, item, false)
+		}
+
+		return item, err
+	})
+
+	select {
+	case <-ctx.Done():
+		return item, fmt.Errorf("cached: %w", ctx.Err())
+	case read := <-reads:
+		if read.Err != nil {
+			return item, fmt.Errorf("cached: %w", read.Err)
+		}
+
+		return read.Val.(T), nil //nolint:forcetypeassert // Only items of type T are read.
+	}
+}
+
+// GetMany items from the cache, reading those that aren't cached from the Store together.
+func (c *Cached[T]) GetMany(ctx context.Context, keys []Key) ([]T, error) {
+	if inTransaction(ctx) {
+		return c.Store.GetMany(ctx, keys)
+	}
+
+	found := make(map[string]T, len(keys))
+
+	var missed []Key
+
+	for _, key := range keys {
+		entry, ok := c.lookup(key)
+
+		switch {
+		case !ok:
+			missed = append(missed, key)
+		case entry.found:
+			found[key.String()] = entry.item
+		}
+	}
+
+	c.record(ctx, cacheHits, int64(len(keys)-len(missed)))
+
+	if len(missed) == 0 {
+		return inKeyOrder(keys, found), nil
+	}
+
+	c.record(ctx, cacheMisses, int64(len(missed)))
+
+	items, err := c.Store.GetMany(ctx, missed)
+	if err != nil {
+		return nil, fmt.Errorf("cached: %w", err)
+	}
+
+	for _, item := range items {
+		found[item.Key().String()] = item
+	}
+
+	for _, key := range missed {
+		item, ok := found[key.String()]
+		c.add(key, item, ok)
+	}
+
+	return inKeyOrder(keys, found), nil
+}
+
+// Delete an item and its children, invalidating any cached copy.
+func (c *Cached[T]) Delete(ctx context.Context, key Key) error {
+	err := c.Store.Delete(ctx, key)
+
+	c.invalidate(Prefix(key))
+
+	return err
+}
+
+// DeleteRange of items, invalidating any cached copies.
+func (c *Cached[T]) DeleteRange(ctx context.Context, r KeyRange) error {
+	err := c.Store.DeleteRange(ctx, r)
+
+	c.invalidate(r)
+
+	return err
+}
+
+// lookup an unexpired entry for a key, marking it as recently read.
+func (c *Cached[T]) lookup(key Key) (*cacheEntry[T], bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key.String()]
+	if !ok {
+		return nil, false
+	}
+
+	entry := c.entry(element)
+	if !entry.expires.IsZero() && !c.now().Before(entry.expires) {
+		c.recent.Remove(element)
+		delete(c.entries, key.String())
+
+		return nil, false
+	}
+
+	c.recent.MoveToFront(element)
+
+	return entry, true
+}
+
+// add an entry for a key read from the Store, evicting the least recently read if the cache is full.
+func (c *Cached[T]) add(key Key, item T, found bool) {
+	ttl := c.opts.TTL
+	if !found {
+		ttl = c.opts.NegativeTTL
+
+		if ttl == 0 {
+			return
+		}
+	}
+
+	entry := &cacheEntry[T]{key: key, item: item, found: found}
+	if ttl > 0 {
+		entry.expires = c.now().Add(ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key.String()]; ok {
+		element.Value = entry
+		c.recent.MoveToFront(element)
+
+		return
+	}
+
+	c.entries[key.String()] = c.recent.PushFront(entry)
+
+	if c.opts.Size > 0 && c.recent.Len() > c.opts.Size {
+		oldest := c.recent.Back()
+		c.recent.Remove(oldest)
+		delete(c.entries, c.entry(oldest).key.String())
+	}
+}
+
+// invalidate entries for keys in a range.
+func (c *Cached[T]) invalidate(r KeyRange) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, element := range c.entries {
+		if r.contains(c.entry(element).key) {
+			c.recent.Remove(element)
+			delete(c.entries, key)
+		}
+	}
+}
+
+// entry held in an element of the recently read list.
+func (c *Cached[T]) entry(element *list.Element) *cacheEntry[T] {
+	return element.Value.(*cacheEntry[T]) //nolint:forcetypeassert // Only entries of type T are kept.
+}
+
+// record reads against the cache's table.
+func (c *Cached[T]) record(ctx context.Context, measure *stats.Int64Measure, n int64) {
+	var item T
+
+	if n == 0 {
+		return
+	}
+
+	// Recording only fails for invalid tags, and the table is always valid.
+	_ = stats.RecordWithTags(ctx, []tag.Mutator{tag.Upsert(tableTag, item.Table())}, measure.M(n))
+}
+
+// inTransaction reports whether a context carries a transaction from any backend.
+func inTransaction(ctx context.Context) bool {
+	return ctx.Value(transactionKey{}) != nil
+}
@@ -0,0 +1,235 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// counted reads passed through to a Store, optionally held until released.
+type counted[T Storable] struct {
+	Store[T]
+	reads   atomic.Int64
+	release chan struct{}
+}
+
+func (c *counted[T]) Get(ctx context.Context, key Key) (T, error) {
+	c.reads.Add(1)
+
+	if c.release != nil {
+		select {
+		case <-c.release:
+		case <-ctx.Done():
+			var item T
+
+			return item, ctx.Err()
+		}
+	}
+
+	return c.Store.Get(ctx, key)
+}
+
+func (c *counted[T]) GetMany(ctx context.Context, keys []Key) ([]T, error) {
+	c.reads.Add(int64(len(keys)))
+
+	return c.Store.GetMany(ctx, keys)
+}
+
+func TestCached(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	accounts := &counted[account]{Store: InMemory[account]()}
+	cached := Cache[account](accounts, CacheOptions{Size: 2, TTL: time.Minute, NegativeTTL: time.Second})
+
+	now := time.Now()
+	cached.now = func() time.Time { return now }
+
+	for _, address := range []string{"a", "b", "c"} {
+		if err := cached.Insert(ctx, account{Address: address, Balance: "1"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := cached.Get(ctx, Key{"a"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := cached.Get(ctx, Key{"z"}); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("want: %v, got: %v", ErrNotFound, err)
+		}
+	}
+
+	if got := accounts.reads.Load(); got != 2 {
+		t.Errorf("reads of cached and missing keys, want: 2, got: %d", got)
+	}
+
+	// Missing keys are forgotten sooner than items.
+	now = now.Add(2 * time.Second)
+
+	if _, err := cached.Get(ctx, Key{"z"}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("want: %v, got: %v", ErrNotFound, err)
+	}
+
+	if _, err := cached.Get(ctx, Key{"a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := accounts.reads.Load(); got != 3 {
+		t.Errorf("reads after negative TTL, want: 3, got: %d", got)
+	}
+
+	// Reading "b" evicts "z", the least recently read, leaving "a" cached.
+	many, err := cached.GetMany(ctx, []Key{{"b"}, {"a"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(many) != 2 || many[0].Address != "b" || many[1].Address != "a" {
+		t.Errorf("get many, want: [b a], got: %v", many)
+	}
+
+	if got := accounts.reads.Load(); got != 4 {
+		t.Errorf("reads for get many, want: 4, got: %d", got)
+	}
+
+	if err := cached.Insert(ctx, account{Address: "a", Balance: "2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := cached.Get(ctx, Key{"a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Balance != "2" {
+		t.Errorf("balance after insert, want: 2, got: %s", got.Balance)
+	}
+
+	now = now.Add(time.Hour)
+
+	if _, err := cached.Get(ctx, Key{"a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := accounts.reads.Load(); got != 6 {
+		t.Errorf("reads after insert and TTL, want: 6, got: %d", got)
+	}
+}
+
+func TestCachedConcurrentReads(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	accounts := &counted[account]{Store: InMemory[account](), release: make(chan struct{})}
+	cached := Cache[account](accounts, CacheOptions{NegativeTTL: time.Minute})
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if _, err := cached.Get(ctx, Key{"a"}); !errors.Is(err, ErrNotFound) {
+				t.Errorf("want: %v, got: %v", ErrNotFound, err)
+			}
+		}()
+	}
+
+	// Readers either wait on the first or find its result cached, so none read again.
+	time.Sleep(10 * time.Millisecond)
+	close(accounts.release)
+	wg.Wait()
+
+	if got := accounts.reads.Load(); got != 1 {
+		t.Errorf("reads, want: 1, got: %d", got)
+	}
+}
+
+func TestCachedCancelledRead(t *testing.T) {
+	t.Parallel()
+
+	accounts := &counted[account]{Store: InMemory[account](), release: make(chan struct{})}
+	cached := Cache[account](accounts, CacheOptions{})
+
+	if err := accounts.Store.Insert(context.Background(), account{Address: "a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error)
+
+	go func() {
+		_, err := cached.Get(ctx, Key{"a"})
+		first <- err
+	}()
+
+	for accounts.reads.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// The first caller gives up, but the read it started carries on for the next.
+	cancel()
+
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled read, want: %v, got: %v", context.Canceled, err)
+	}
+
+	second := make(chan error)
+
+	go func() {
+		_, err := cached.Get(context.Background(), Key{"a"})
+		second <- err
+	}()
+
+	close(accounts.release)
+
+	if err := <-second; err != nil {
+		t.Errorf("shared read: %v", err)
+	}
+
+	if got := accounts.reads.Load(); got != 1 {
+		t.Errorf("reads, want: 1, got: %d", got)
+	}
+}
+
+func TestCachedReadTimeout(t *testing.T) {
+	t.Parallel()
+
+	accounts := &counted[account]{Store: InMemory[account](), release: make(chan struct{})}
+	cached := Cache[account](accounts, CacheOptions{ReadTimeout: 10 * time.Millisecond})
+
+	if _, err := cached.Get(context.Background(), Key{"a"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("want: %v, got: %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestCachedInTransaction(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cached := Cache[account](InMemory[account](), CacheOptions{NegativeTTL: time.Minute})
+
+	if _, err := cached.Get(ctx, Key{"a"}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("want: %v, got: %v", ErrNotFound, err)
+	}
+
+	err := TransactInMemory().InTransaction(ctx, func(ctx context.Context) error {
+		if err := cached.Insert(ctx, account{Address: "a"}); err != nil {
+			return err
+		}
+
+		_, err := cached.Get(ctx, Key{"a"})
+
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
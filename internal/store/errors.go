@@ -0,0 +1,139 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/grpc/codes"
+)
+
+// Kinds of error returned by every Store, found with errors.Is.
+var (
+	// ErrNotFound indicates no item has the requested key.
+	ErrNotFound = errors.New("not found")
+	// ErrUnavailable indicates the database couldn't be reached, and the request may succeed if retried later.
+	ErrUnavailable = errors.New("database unavailable")
+	// ErrDeadlineExceeded indicates the request wasn't answered before its context's deadline.
+	ErrDeadlineExceeded = errors.New("deadline exceeded")
+	// ErrCanceled indicates the request's context was cancelled.
+	ErrCanceled = errors.New("canceled")
+	// ErrConflict indicates a transaction kept conflicting with others and was given up.
+	ErrConflict = errors.New("transaction conflict")
+)
+
+// kinds of error that errors are classified by.
+//
+//nolint:gochecknoglobals // Immutable list of the errors above
+var kinds = []error{ErrNotFound, ErrUnavailable, ErrDeadlineExceeded, ErrCanceled, ErrConflict}
+
+// A classified error from a database, matching its kind with errors.Is while keeping the original error.
+type classified struct {
+	kind error
+	err  error
+}
+
+// Error describes the kind of error and the original.
+func (c classified) Error() string {
+	return fmt.Sprintf("%v: %v", c.kind, c.err)
+}
+
+// Unwrap to the original error.
+func (c classified) Unwrap() error {
+	return c.err
+}
+
+// Is the error of the given kind.
+func (c classified) Is(target error) bool {
+	return target == c.kind
+}
+
+// classify an error as kind, unless it already has one.
+func classify(err error, kind error) error {
+	if kind == nil || isClassified(err) {
+		return err
+	}
+
+	return classified{kind: kind, err: err}
+}
+
+// isClassified reports whether an error already has a kind.
+func isClassified(err error) bool {
+	for _, kind := range kinds {
+		if errors.Is(err, kind) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// notFound error for an item missing from a table.
+func notFound(table string, key Key) error {
+	return fmt.Errorf("%w: %s %v", ErrNotFound, table, key)
+}
+
+// contextKind of an error caused by its context ending, or nil.
+func contextKind(err error) error {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrDeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		return ErrCanceled
+	default:
+		return nil
+	}
+}
+
+// spannerError classified by its code.
+func spannerError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var spannerErr *spanner.Error
+	if !errors.As(err, &spannerErr) {
+		return classify(err, contextKind(err))
+	}
+
+	// NotFound is left unclassified, as Spanner also uses it for missing tables and databases. Stores report missing
+	// items themselves.
+	//
+	//nolint:exhaustive // Other codes are left unclassified.
+	switch spannerErr.Code {
+	case codes.Unavailable, codes.ResourceExhausted:
+		return classify(err, ErrUnavailable)
+	case codes.DeadlineExceeded:
+		return classify(err, ErrDeadlineExceeded)
+	case codes.Canceled:
+		return classify(err, ErrCanceled)
+	case codes.Aborted:
+		return classify(err, ErrConflict)
+	default:
+		return err
+	}
+}
+
+// sqlError classified by its cause, using the dialect to recognise conflicts.
+func (d dialect) sqlError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var netErr net.Error
+
+	switch {
+	case contextKind(err) != nil:
+		return classify(err, contextKind(err))
+	case errors.Is(err, driver.ErrBadConn), errors.Is(err, sql.ErrConnDone), errors.As(err, &netErr):
+		return classify(err, ErrUnavailable)
+	case d.retryable(err):
+		return classify(err, ErrConflict)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,61 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSpannerError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		err  error
+		want error
+	}{
+		{spanner.ToSpannerError(status.Error(codes.Unavailable, "connection refused")), ErrUnavailable},
+		{spanner.ToSpannerError(status.Error(codes.DeadlineExceeded, "timed out")), ErrDeadlineExceeded},
+		{spanner.ToSpannerError(status.Error(codes.Aborted, "aborted")), ErrConflict},
+		{fmt.Errorf("wrapped: %w", context.Canceled), ErrCanceled},
+	}
+
+	for _, tt := range tests {
+		err := spannerError(tt.err)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%v, want: %v, got: %v", tt.err, tt.want, err)
+		}
+
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%v, original error lost: %v", tt.err, err)
+		}
+	}
+
+	for _, code := range []codes.Code{codes.Internal, codes.NotFound} {
+		unclassified := spanner.ToSpannerError(status.Error(code, "Table not found: Blocks"))
+		if err := spannerError(unclassified); isClassified(err) {
+			t.Errorf("%v error classified: %v", code, err)
+		}
+	}
+
+	// Errors that already have a kind keep it.
+	if err := spannerError(notFound("Accounts", Key{"a"})); !errors.Is(err, ErrNotFound) || errors.Is(err, ErrConflict) {
+		t.Errorf("reclassified: %v", err)
+	}
+}
+
+func TestSQLError(t *testing.T) {
+	t.Parallel()
+
+	if err := sqliteDialect.sqlError(context.DeadlineExceeded); !errors.Is(err, ErrDeadlineExceeded) {
+		t.Errorf("want: %v, got: %v", ErrDeadlineExceeded, err)
+	}
+
+	if err := sqliteDialect.sqlError(errRollback); isClassified(err) {
+		t.Errorf("unknown error classified: %v", err)
+	}
+}
@@ -0,0 +1,335 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// A memoryTransaction carries the writes made in a unit of work until it commits.
+type memoryTransaction struct {
+	pending
+	writes []func()
+}
+
+// buffer a write to apply when the transaction commits.
+func (t *memoryTransaction) buffer(item Storable, write func()) {
+	t.put(item)
+
+	t.Lock()
+	defer t.Unlock()
+
+	t.writes = append(t.writes, write)
+}
+
+// bufferDelete of a range from a table, to apply when the transaction commits.
+func (t *memoryTransaction) bufferDelete(table string, r KeyRange, write func()) {
+	t.remove(table, r)
+
+	t.Lock()
+	defer t.Unlock()
+
+	t.writes = append(t.writes, write)
+}
+
+// MemoryTransactor runs units of work against Memory stores, one at a time.
+type MemoryTransactor struct {
+	mu sync.Mutex
+}
+
+// TransactInMemory runs units of work against Memory stores.
+func TransactInMemory() *MemoryTransactor {
+	return &MemoryTransactor{}
+}
+
+// InTransaction runs fn, applying all writes made to Memory stores if it returns nil.
+func (m *MemoryTransactor) InTransaction(ctx context.Context, fn func(context.Context) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	txn := &memoryTransaction{}
+
+	if err := fn(context.WithValue(ctx, transactionKey{}, txn)); err != nil {
+		return fmt.Errorf("transaction: %w", err)
+	}
+
+	for _, write := range txn.writes {
+		write()
+	}
+
+	return nil
+}
+
+// Memory store for elements, for tests and local development.
+type Memory[T Storable] struct {
+	mu       sync.RWMutex
+	items    map[string]T
+	children []RangeDeleter
+}
+
+// A RangeDeleter deletes ranges of elements, as every Store does.
+type RangeDeleter interface {
+	DeleteRange(context.Context, KeyRange) error
+}
+
+// InMemory stores items in a map. It is safe for concurrent use.
+func InMemory[T Storable]() *Memory[T] {
+	return &Memory[T]{items: make(map[string]T)}
+}
+
+// Interleave child stores in this one, so deleting elements also deletes the child elements whose keys begin with
+// theirs, as with INTERLEAVE IN PARENT ... ON DELETE CASCADE. It must be called before the store is used.
+func (m *Memory[T]) Interleave(children ...RangeDeleter) *Memory[T] {
+	m.children = append(m.children, children...)
+
+	return m
+}
+
+// Insert an item into the store, replacing any with the same key. Within a transaction the write is buffered until
+// it commits.
+func (m *Memory[T]) Insert(ctx context.Context, item T) error {
+	write := func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		m.items[item.Key().String()] = item
+	}
+
+	if txn, ok := ctx.Value(transactionKey{}).(*memoryTransaction); ok {
+		txn.buffer(item, write)
+
+		return nil
+	}
+
+	write()
+
+	return nil
+}
+
+// Get an element from the store by key. Within a transaction, items written earlier in it are returned.
+func (m *Memory[T]) Get(ctx context.Context, key Key) (T, error) {
+	var item T
+
+	if txn, ok := ctx.Value(transactionKey{}).(*memoryTransaction); ok {
+		if pending, ok := txn.get(item.Table(), key); ok {
+			if pending == nil {
+				return item, fmt.Errorf("get: %w", notFound(item.Table(), key))
+			}
+
+			//nolint:forcetypeassert // Items are only ever buffered under their own table.
+			return pending.(T), nil
+		}
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	item, ok := m.items[key.String()]
+	if !ok {
+		return item, fmt.Errorf("get: %w", notFound(item.Table(), key))
+	}
+
+	return item, nil
+}
+
+// GetMany elements from the store, in the order of their keys. Keys with no element are skipped. Within a
+// transaction, items written earlier in it are returned.
+func (m *Memory[T]) GetMany(ctx context.Context, keys []Key) ([]T, error) {
+	var item T
+
+	found := make(map[string]T, len(keys))
+
+	txn, inTransaction := ctx.Value(transactionKey{}).(*memoryTransaction)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, key := range keys {
+		if inTransaction {
+			if pending, ok := txn.get(item.Table(), key); ok {
+				if pending != nil {
+					//nolint:forcetypeassert // Items are only ever buffered under their own table.
+					found[key.String()] = pending.(T)
+				}
+
+				continue
+			}
+		}
+
+		if item, ok := m.items[key.String()]; ok {
+			found[key.String()] = item
+		}
+	}
+
+	return inKeyOrder(keys, found), nil
+}
+
+// Delete an element from the store by key, along with any in child stores interleaved in it. Within a transaction
+// the delete is buffered until it commits.
+func (m *Memory[T]) Delete(ctx context.Context, key Key) error {
+	if err := m.DeleteRange(ctx, Prefix(key)); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteRange of elements from the store, along with any in child stores interleaved in them. Within a transaction
+// the delete is buffered until it commits.
+func (m *Memory[T]) DeleteRange(ctx context.Context, r KeyRange) error {
+	var item T
+
+	write := func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		for key, item := range m.items {
+			if r.contains(item.Key()) {
+				delete(m.items, key)
+			}
+		}
+	}
+
+	if txn, ok := ctx.Value(transactionKey{}).(*memoryTransaction); ok {
+		txn.bufferDelete(item.Table(), r, write)
+	} else {
+		write()
+	}
+
+	for _, child := range m.children {
+		if err := child.DeleteRange(ctx, r); err != nil {
+			return fmt.Errorf("delete range: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// List elements matching the item's List query, with limit and offset for pagination. A limit of zero lists all
+// elements. Writes buffered in a transaction are not included.
+func (m *Memory[T]) List(_ context.Context, prefix Key, limit, offset int64) ([]T, error) {
+	var item T
+
+	return m.query(item.List(prefix, limit, offset)), nil
+}
+
+// ListMany elements matching the List query for any of the prefixes, in its order. Writes buffered in a transaction
+// are not included.
+func (m *Memory[T]) ListMany(_ context.Context, prefixes []Key) ([]T, error) {
+	if len(prefixes) == 0 {
+		return nil, nil
+	}
+
+	return m.query(manyQuery[T](prefixes)), nil
+}
+
+// Select elements matching a query, such as one over a secondary index, in its order. Writes buffered in a
+// transaction are not included.
+func (m *Memory[T]) Select(_ context.Context, q Query) ([]T, error) {
+	return m.query(q), nil
+}
+
+// Page lists up to limit elements after the one with the given key, in the order of the List query. A nil key
+// starts from the first element.
+func (m *Memory[T]) Page(ctx context.Context, prefix, after Key, limit int64) ([]T, error) {
+	q, err := pageQuery(ctx, m.Get, prefix, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("page: %w", err)
+	}
+
+	return m.query(q), nil
+}
+
+// query for the elements matching a query.
+func (m *Memory[T]) query(q Query) []T {
+	m.mu.RLock()
+
+	items := make([]T, 0, len(m.items))
+
+	for _, item := range m.items {
+		if !matches(item, q.Filters) || !matchesAny(item, q.Alternatives) {
+			continue
+		}
+
+		if q.After != nil && compareValues(orderValues(item, q.Order), q.After, q.Order) <= 0 {
+			continue
+		}
+
+		items = append(items, item)
+	}
+
+	m.mu.RUnlock()
+
+	// Items equal in every order are sorted by key, so pages are stable.
+	sort.Slice(items, func(i, j int) bool {
+		if c := compareValues(orderValues(items[i], q.Order), orderValues(items[j], q.Order), q.Order); c != 0 {
+			return c < 0
+		}
+
+		return compareKeys(items[i].Key(), items[j].Key()) < 0
+	})
+
+	if q.Offset >= int64(len(items)) {
+		return nil
+	}
+
+	items = items[q.Offset:]
+
+	if q.Limit > 0 && q.Limit < int64(len(items)) {
+		items = items[:q.Limit]
+	}
+
+	return items
+}
+
+// matches reports whether an item satisfies every filter.
+func matches(item any, filters []Filter) bool {
+	for _, filter := range filters {
+		value := column(item, filter.Column)
+
+		switch filter.Operator {
+		case OperatorEqual:
+			if value == nil || compareParts(value, filter.Value) != 0 {
+				return false
+			}
+		case OperatorNotTrue:
+			if set, ok := value.(bool); ok && set {
+				return false
+			}
+		case OperatorAtLeast:
+			if value == nil || compareParts(value, filter.Value) < 0 {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// matchesAny reports whether an item satisfies every filter of at least one alternative, or there are none.
+func matchesAny(item any, alternatives [][]Filter) bool {
+	for _, filters := range alternatives {
+		if matches(item, filters) {
+			return true
+		}
+	}
+
+	return len(alternatives) == 0
+}
+
+// compareValues of two items in each order column in turn.
+func compareValues(a, b []any, orders []Order) int {
+	for i, order := range orders {
+		c := compareParts(a[i], b[i])
+		if order.Descending {
+			c = -c
+		}
+
+		if c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
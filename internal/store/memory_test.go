@@ -0,0 +1,318 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// output is keyed by a hash and index, listed from the last index unless spent.
+type output struct {
+	Hash  string
+	Index int64
+	Spent *bool
+}
+
+func (o output) Key() Key {
+	return Key{o.Hash, o.Index}
+}
+
+func (output) Table() string {
+	return "Outputs"
+}
+
+func (output) List(prefix Key, limit, offset int64) Query {
+	return Query{
+		Filters: []Filter{Equal("Hash", prefix[0]), NotTrue("Spent")},
+		Order:   []Order{Descending("Index")},
+		Limit:   limit,
+		Offset:  offset,
+	}
+}
+
+func TestMemoryList(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	accounts := InMemory[account]()
+
+	for _, address := range []string{"c", "a", "d", "b"} {
+		if err := accounts.Insert(ctx, account{Address: address}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := accounts.List(ctx, nil, 2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 2 || got[0].Address != "b" || got[1].Address != "c" {
+		t.Errorf("limit and offset, want: [b c], got: %v", got)
+	}
+
+	if got, _ := accounts.List(ctx, nil, 1, 4); len(got) != 0 {
+		t.Errorf("offset past end, want: [], got: %v", got)
+	}
+
+	outputs := InMemory[output]()
+
+	spent, unspent := true, false
+
+	for _, o := range []output{{"a", 0, nil}, {"a", 1, &spent}, {"a", 2, &unspent}, {"b", 0, nil}} {
+		if err := outputs.Insert(ctx, o); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	listed, err := outputs.List(ctx, Key{"a"}, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(listed) != 2 || listed[0].Index != 2 || listed[1].Index != 0 {
+		t.Errorf("prefix, filter and order, want: [a/2 a/0], got: %v", listed)
+	}
+
+	selected, err := outputs.Select(ctx, Query{Filters: []Filter{AtLeast("Index", int64(1))}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(selected) != 2 {
+		t.Errorf("at least index 1, want: [a/1 a/2], got: %v", selected)
+	}
+
+	many, err := outputs.ListMany(ctx, []Key{{"b"}, {"a"}, {"c"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(many) != 3 || many[0].Index != 2 || many[1].Hash != "a" || many[2].Hash != "b" {
+		t.Errorf("list many, want: [a/2 a/0 b/0], got: %v", many)
+	}
+}
+
+func TestMemoryPage(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	outputs := InMemory[output]()
+	spent := true
+
+	for index := int64(0); index < 5; index++ {
+		o := output{Hash: "a", Index: index}
+		if index == 3 {
+			o.Spent = &spent
+		}
+
+		if err := outputs.Insert(ctx, o); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var (
+		pages [][]int64
+		after Key
+	)
+
+	for {
+		page, err := outputs.Page(ctx, Key{"a"}, after, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(page) == 0 {
+			break
+		}
+
+		var indexes []int64
+		for _, o := range page {
+			indexes = append(indexes, o.Index)
+		}
+
+		pages = append(pages, indexes)
+		after = page[len(page)-1].Key()
+	}
+
+	if want := [][]int64{{4, 2}, {1, 0}}; !reflect.DeepEqual(pages, want) {
+		t.Errorf("pages, want: %v, got: %v", want, pages)
+	}
+
+	if _, err := outputs.Page(ctx, Key{"a"}, Key{"a", int64(9)}, 2); err == nil {
+		t.Error("want error paging after a missing item")
+	}
+}
+
+func TestMemoryGet(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	accounts := InMemory[account]()
+
+	if _, err := accounts.Get(ctx, Key{"a"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing item, want: %v, got: %v", ErrNotFound, err)
+	}
+
+	if err := accounts.Insert(ctx, account{Address: "a", Balance: "1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := accounts.Get(ctx, Key{"a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Balance != "1" {
+		t.Errorf("balance, want: %s, got: %s", "1", got.Balance)
+	}
+}
+
+func TestMemoryGetMany(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	accounts := InMemory[account]()
+	transactor := TransactInMemory()
+
+	for _, address := range []string{"a", "b"} {
+		if err := accounts.Insert(ctx, account{Address: address}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := transactor.InTransaction(ctx, func(ctx context.Context) error {
+		if err := accounts.Insert(ctx, account{Address: "c"}); err != nil {
+			return err
+		}
+
+		got, err := accounts.GetMany(ctx, []Key{{"c"}, {"x"}, {"a"}})
+		if err != nil {
+			return err
+		}
+
+		if len(got) != 2 || got[0].Address != "c" || got[1].Address != "a" {
+			t.Errorf("want: [c a], got: %v", got)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMemoryInTransaction(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	accounts := InMemory[account]()
+	transactor := TransactInMemory()
+
+	err := transactor.InTransaction(ctx, func(ctx context.Context) error {
+		if err := accounts.Insert(ctx, account{Address: "a", Balance: "1"}); err != nil {
+			return err
+		}
+
+		if _, err := accounts.Get(context.Background(), Key{"a"}); err == nil {
+			t.Error("write visible outside transaction before commit")
+		}
+
+		got, err := accounts.Get(ctx, Key{"a"})
+		if err != nil {
+			return err
+		}
+
+		if got.Balance != "1" {
+			t.Errorf("read within transaction, want: %s, got: %s", "1", got.Balance)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := accounts.Get(ctx, Key{"a"}); err != nil {
+		t.Errorf("read after commit: %v", err)
+	}
+
+	err = transactor.InTransaction(ctx, func(ctx context.Context) error {
+		if err := accounts.Insert(ctx, account{Address: "b"}); err != nil {
+			return err
+		}
+
+		return errRollback
+	})
+	if err == nil {
+		t.Fatal("want error from rolled back transaction")
+	}
+
+	if _, err := accounts.Get(ctx, Key{"b"}); err == nil {
+		t.Error("write visible after rollback")
+	}
+}
+
+func TestMemoryDelete(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	outputs := InMemory[output]()
+	accounts := InMemory[account]().Interleave(outputs)
+	transactor := TransactInMemory()
+
+	for _, address := range []string{"a", "b", "c"} {
+		if err := accounts.Insert(ctx, account{Address: address}); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := outputs.Insert(ctx, output{Hash: address, Index: 0}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := transactor.InTransaction(ctx, func(ctx context.Context) error {
+		if err := accounts.Delete(ctx, Key{"a"}); err != nil {
+			return err
+		}
+
+		if _, err := accounts.Get(ctx, Key{"a"}); err == nil {
+			t.Error("deleted item visible within transaction")
+		}
+
+		if _, err := outputs.Get(ctx, Key{"a", int64(0)}); err == nil {
+			t.Error("interleaved item visible within transaction")
+		}
+
+		if _, err := accounts.Get(context.Background(), Key{"a"}); err != nil {
+			t.Errorf("delete visible outside transaction before commit: %v", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := outputs.Get(ctx, Key{"a", int64(0)}); err == nil {
+		t.Error("interleaved item not deleted")
+	}
+
+	if err := accounts.DeleteRange(ctx, KeyRange{Start: Key{"c"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := outputs.List(ctx, Key{"b"}, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 1 {
+		t.Errorf("item outside range, want: [b/0], got: %v", got)
+	}
+
+	if _, err := accounts.Get(ctx, Key{"c"}); err == nil {
+		t.Error("item in range not deleted")
+	}
+}
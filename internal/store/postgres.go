@@ -0,0 +1,45 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// Postgres error codes indicating a transaction conflicted with another and may be retried.
+const (
+	serializationFailure = "40001"
+	deadlockDetected     = "40P01"
+)
+
+// postgresDialect for Postgres. Identifiers are left unquoted so they fold to lower case, matching however they
+// are written.
+//
+//nolint:gochecknoglobals // Immutable dialect
+var postgresDialect = dialect{
+	placeholder: func(n int) string { return fmt.Sprintf("$%d", n) },
+	quote:       func(identifier string) string { return identifier },
+	array:       func(a any) any { return pq.Array(a) },
+	now:         "now()",
+	keyColumns: `SELECT a.attname FROM pg_index i
+		JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = ANY(i.indkey)
+		WHERE i.indrelid = $1::regclass AND i.indisprimary
+		ORDER BY array_position(i.indkey::int2[], a.attnum)`,
+	retryable: func(err error) bool {
+		var pqErr *pq.Error
+
+		return errors.As(err, &pqErr) && (pqErr.Code == serializationFailure || pqErr.Code == deadlockDetected)
+	},
+}
+
+// TransactInPostgres runs units of work against a Postgres database.
+func TransactInPostgres(db *sql.DB) *SQLTransactor {
+	return &SQLTransactor{db: db, dialect: postgresDialect}
+}
+
+// InPostgres stores items backed by a Postgres database.
+func InPostgres[T Storable](db *sql.DB) *SQL[T] {
+	return &SQL[T]{db: db, dialect: postgresDialect}
+}
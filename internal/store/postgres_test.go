@@ -0,0 +1,123 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// block with an array column and commit timestamp, as stored by the indexer.
+type block struct {
+	Height      int64
+	Hashes      []string
+	Missing     *bool
+	ObserveTime time.Time
+}
+
+func (b block) Key() Key {
+	return Key{b.Height}
+}
+
+func (block) Table() string {
+	return "Blocks"
+}
+
+func (block) List(_ Key, limit, offset int64) Query {
+	return Query{Order: []Order{Descending("Height")}, Limit: limit, Offset: offset}
+}
+
+func TestUpsert(t *testing.T) {
+	t.Parallel()
+
+	query, args := upsert(postgresDialect, block{Height: 1, ObserveTime: CommitTimestamp}, []string{"height"})
+
+	want := "INSERT INTO Blocks (Height, Hashes, Missing, ObserveTime) VALUES ($1, $2, $3, now()) " +
+		"ON CONFLICT (height) DO UPDATE SET Hashes = EXCLUDED.Hashes, Missing = EXCLUDED.Missing, " +
+		"ObserveTime = EXCLUDED.ObserveTime"
+	if query != want {
+		t.Errorf("query, want: %s, got: %s", want, query)
+	}
+
+	if len(args) != 3 {
+		t.Errorf("args, want: 3, got: %d", len(args))
+	}
+
+	query, _ = upsert(postgresDialect, account{Address: "a"}, []string{"address", "balance"})
+
+	want = "INSERT INTO Accounts (Address, Balance) VALUES ($1, $2) ON CONFLICT (address, balance) DO NOTHING"
+	if query != want {
+		t.Errorf("query, want: %s, got: %s", want, query)
+	}
+}
+
+// TestPostgres against a database given by POSTGRES_TEST_URL, which is expected to be empty.
+func TestPostgres(t *testing.T) {
+	t.Parallel()
+
+	url := os.Getenv("POSTGRES_TEST_URL")
+	if url == "" {
+		t.Skip("POSTGRES_TEST_URL not set")
+	}
+
+	ctx := context.Background()
+
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.ExecContext(ctx, "CREATE TABLE Blocks "+
+		"(Height BIGINT PRIMARY KEY, Hashes TEXT[], Missing BOOLEAN, ObserveTime TIMESTAMPTZ NOT NULL)"); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _, _ = db.ExecContext(ctx, "DROP TABLE Blocks") })
+
+	blocks := InPostgres[block](db)
+	transactor := TransactInPostgres(db)
+	missing := true
+
+	err = transactor.InTransaction(ctx, func(ctx context.Context) error {
+		for height := int64(1); height <= 3; height++ {
+			b := block{Height: height, Hashes: []string{"a", "b"}, ObserveTime: CommitTimestamp}
+			if err := blocks.Insert(ctx, b); err != nil {
+				return err
+			}
+		}
+
+		b, err := blocks.Get(ctx, Key{int64(2)})
+		if err != nil {
+			return err
+		}
+
+		b.Missing = &missing
+
+		return blocks.Insert(ctx, b)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := blocks.Get(ctx, Key{int64(2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got.Hashes, []string{"a", "b"}) || got.Missing == nil || !*got.Missing {
+		t.Errorf("get, got: %+v", got)
+	}
+
+	listed, err := blocks.List(ctx, nil, 2, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(listed) != 2 || listed[0].Height != 3 || listed[1].Height != 2 {
+		t.Errorf("list, want: [3 2], got: %+v", listed)
+	}
+}
@@ -0,0 +1,356 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+
+	"cloud.google.com/go/spanner"
+)
+
+// CommitTimestamp written to a time column is replaced by the time the write is committed.
+//
+//nolint:gochecknoglobals // Sentinel value recognised by every backend
+var CommitTimestamp = spanner.CommitTimestamp
+
+// A Key identifies an item by the values of its primary key columns, in order. A Key with fewer parts than the
+// primary key is a prefix of it.
+type Key []any
+
+// String representation of the key, distinct for keys of different values or types.
+func (k Key) String() string {
+	parts := make([]string, len(k))
+	for i, part := range k {
+		parts[i] = fmt.Sprintf("%#v", part)
+	}
+
+	return "(" + strings.Join(parts, ",") + ")"
+}
+
+// A KeyRange covers the keys from Start to End inclusive, where each bound also covers every key beginning with it.
+// An empty bound leaves that end of the range open, so the range with neither covers every key.
+type KeyRange struct {
+	Start Key
+	End   Key
+}
+
+// Prefix range covering every key beginning with the given key.
+func Prefix(key Key) KeyRange {
+	return KeyRange{Start: key, End: key}
+}
+
+// contains reports whether the key is in the range.
+func (r KeyRange) contains(key Key) bool {
+	return compareBound(key, r.Start) >= 0 && compareBound(key, r.End) <= 0
+}
+
+// compareBound of a key to a range bound, comparing only as many parts as the bound has.
+func compareBound(key, bound Key) int {
+	if len(key) > len(bound) {
+		key = key[:len(bound)]
+	}
+
+	return compareKeys(key, bound)
+}
+
+// inKeyOrder lists the items found for each key, skipping keys with none.
+func inKeyOrder[T any](keys []Key, found map[string]T) []T {
+	items := make([]T, 0, len(found))
+
+	for _, key := range keys {
+		if item, ok := found[key.String()]; ok {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// An Operator compares a column in a Filter.
+type Operator int
+
+// Operators supported by every backend.
+const (
+	// OperatorEqual matches items where the column equals the value.
+	OperatorEqual Operator = iota
+	// OperatorNotTrue matches items where a boolean column is null or false.
+	OperatorNotTrue
+	// OperatorAtLeast matches items where the column is greater than or equal to the value.
+	OperatorAtLeast
+)
+
+// A Filter restricts the items in a Query by the value of a column.
+type Filter struct {
+	Column   string
+	Operator Operator
+	Value    any
+}
+
+// Equal filters items to those where the column equals the value.
+func Equal(column string, value any) Filter {
+	return Filter{Column: column, Operator: OperatorEqual, Value: value}
+}
+
+// NotTrue filters items to those where a boolean column is null or false.
+func NotTrue(column string) Filter {
+	return Filter{Column: column, Operator: OperatorNotTrue}
+}
+
+// AtLeast filters items to those where the column is greater than or equal to the value.
+func AtLeast(column string, value any) Filter {
+	return Filter{Column: column, Operator: OperatorAtLeast, Value: value}
+}
+
+// An Order sorts the items in a Query by a column.
+type Order struct {
+	Column     string
+	Descending bool
+}
+
+// Ascending orders items from the lowest value of the column.
+func Ascending(column string) Order {
+	return Order{Column: column}
+}
+
+// Descending orders items from the highest value of the column.
+func Descending(column string) Order {
+	return Order{Column: column, Descending: true}
+}
+
+// A Query describes the items a List returns from a table: those matching every filter, sorted by each order in
+// turn, with limit and offset for pagination. A limit of zero returns all items. Each backend translates the query
+// into its own form.
+//
+// For pages listed after an item, After holds the item's values of each order column, and only items sorted after
+// them are returned. The order must then be unique for each item matching the filters.
+//
+// Items must also match every filter in at least one of the Alternatives, if there are any, as when listing under
+// several prefixes at once.
+type Query struct {
+	Filters      []Filter
+	Alternatives [][]Filter
+	Order        []Order
+	After        []any
+	Limit        int64
+	Offset       int64
+}
+
+// manyQuery for the items listed under any of the prefixes, in the order of the List query.
+func manyQuery[T Storable](prefixes []Key) Query {
+	var item T
+
+	q := item.List(prefixes[0], 0, 0)
+	q.Filters = nil
+
+	for _, prefix := range prefixes {
+		q.Alternatives = append(q.Alternatives, item.List(prefix, 0, 0).Filters)
+	}
+
+	return q
+}
+
+// pageQuery for the items listed under a prefix after the one with the given key, or from the first item if the key
+// is nil. The item is read with get to find where it is sorted.
+func pageQuery[T Storable](
+	ctx context.Context, get func(context.Context, Key) (T, error), prefix, after Key, limit int64,
+) (Query, error) {
+	var item T
+
+	q := item.List(prefix, limit, 0)
+
+	if after == nil {
+		return q, nil
+	}
+
+	last, err := get(ctx, after)
+	if err != nil {
+		return q, fmt.Errorf("after %v: %w", after, err)
+	}
+
+	q.After = orderValues(last, q.Order)
+
+	return q, nil
+}
+
+// column of an item by field name, with pointers followed. Nil pointers are returned as nil.
+func column(item any, name string) any {
+	v := reflect.ValueOf(item).FieldByName(name)
+	if !v.IsValid() {
+		panic(fmt.Sprintf("store: %T has no column %s", item, name))
+	}
+
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+
+		v = v.Elem()
+	}
+
+	return v.Interface()
+}
+
+// orderValues of an item, for each order column.
+func orderValues(item any, orders []Order) []any {
+	values := make([]any, len(orders))
+	for i, order := range orders {
+		values[i] = column(item, order.Column)
+	}
+
+	return values
+}
+
+// selectStatement for a query in SQL, quoting columns and numbering parameters as the database expects.
+func selectStatement(table string, q Query, quote func(string) string, placeholder func(int) string) (string, []any) {
+	var (
+		b    strings.Builder
+		args []any
+	)
+
+	param := func(value any) string {
+		args = append(args, value)
+
+		return placeholder(len(args))
+	}
+
+	where := func(filters []Filter) []string {
+		var conditions []string
+
+		for _, filter := range filters {
+			column := quote(filter.Column)
+
+			switch filter.Operator {
+			case OperatorEqual:
+				conditions = append(conditions, fmt.Sprintf("%s = %s", column, param(filter.Value)))
+			case OperatorNotTrue:
+				conditions = append(conditions, fmt.Sprintf("(%s IS NULL OR %s = FALSE)", column, column))
+			case OperatorAtLeast:
+				conditions = append(conditions, fmt.Sprintf("%s >= %s", column, param(filter.Value)))
+			}
+		}
+
+		return conditions
+	}
+
+	conditions := where(q.Filters)
+
+	if len(q.Alternatives) > 0 {
+		alternatives := make([]string, len(q.Alternatives))
+		for i, filters := range q.Alternatives {
+			alternatives[i] = "(" + strings.Join(where(filters), " AND ") + ")"
+		}
+
+		conditions = append(conditions, "("+strings.Join(alternatives, " OR ")+")")
+	}
+
+	// Items sorted after a page are those after it in the first order column, or level with it in the first and
+	// after it in the next, and so on.
+	if q.After != nil {
+		var alternatives []string
+
+		for i, order := range q.Order {
+			var terms []string
+
+			for j, level := range q.Order[:i] {
+				terms = append(terms, fmt.Sprintf("%s = %s", quote(level.Column), param(q.After[j])))
+			}
+
+			comparison := ">"
+			if order.Descending {
+				comparison = "<"
+			}
+
+			terms = append(terms, fmt.Sprintf("%s %s %s", quote(order.Column), comparison, param(q.After[i])))
+			alternatives = append(alternatives, "("+strings.Join(terms, " AND ")+")")
+		}
+
+		conditions = append(conditions, "("+strings.Join(alternatives, " OR ")+")")
+	}
+
+	fmt.Fprintf(&b, "SELECT * FROM %s", table)
+
+	if len(conditions) > 0 {
+		b.WriteString(" WHERE " + strings.Join(conditions, " AND "))
+	}
+
+	for i, order := range q.Order {
+		if i == 0 {
+			b.WriteString(" ORDER BY ")
+		} else {
+			b.WriteString(", ")
+		}
+
+		b.WriteString(quote(order.Column))
+
+		if order.Descending {
+			b.WriteString(" DESC")
+		}
+	}
+
+	// Not every database accepts an offset without a limit.
+	if q.Limit > 0 || q.Offset > 0 {
+		limit := q.Limit
+		if limit == 0 {
+			limit = math.MaxInt64
+		}
+
+		fmt.Fprintf(&b, " LIMIT %s OFFSET %s", param(limit), param(q.Offset))
+	}
+
+	return b.String(), args
+}
+
+// compareKeys part by part, with shorter keys ordered first when one is a prefix of the other.
+func compareKeys(a, b Key) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := compareParts(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+
+	return len(a) - len(b)
+}
+
+// compareParts of keys or columns by their natural order with nulls first, falling back to their formatted value
+// for other types.
+func compareParts(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+
+	switch a := a.(type) {
+	case int64:
+		if b, ok := b.(int64); ok {
+			return compareOrdered(a, b)
+		}
+	case int:
+		if b, ok := b.(int); ok {
+			return compareOrdered(a, b)
+		}
+	case string:
+		if b, ok := b.(string); ok {
+			return strings.Compare(a, b)
+		}
+	}
+
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// compareOrdered values, returning -1, 0 or 1.
+func compareOrdered[V int | int64](a, b V) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
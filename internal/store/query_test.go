@@ -0,0 +1,67 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKeyString(t *testing.T) {
+	t.Parallel()
+
+	if a, b := (Key{"1"}).String(), (Key{int64(1)}).String(); a == b {
+		t.Errorf("keys of different types, want distinct, got: %s %s", a, b)
+	}
+
+	if want, got := `("a",2)`, (Key{"a", int64(2)}).String(); got != want {
+		t.Errorf("want: %s, got: %s", want, got)
+	}
+}
+
+func TestSelectStatement(t *testing.T) {
+	t.Parallel()
+
+	q := Query{
+		Filters: []Filter{Equal("A", "x"), NotTrue("B")},
+		Order:   []Order{Ascending("C"), Descending("D")},
+		Limit:   10,
+		Offset:  20,
+	}
+
+	query, args := selectStatement("T", q, postgresDialect.quote, postgresDialect.placeholder)
+
+	want := "SELECT * FROM T WHERE A = $1 AND (B IS NULL OR B = FALSE) ORDER BY C, D DESC LIMIT $2 OFFSET $3"
+	if query != want {
+		t.Errorf("query, want: %s, got: %s", want, query)
+	}
+
+	if want := []any{"x", int64(10), int64(20)}; !reflect.DeepEqual(args, want) {
+		t.Errorf("args, want: %v, got: %v", want, args)
+	}
+
+	q = Query{Order: []Order{Descending("A"), Ascending("B")}, After: []any{int64(1), "x"}, Limit: 5}
+
+	query, args = selectStatement("T", q, sqliteDialect.quote, sqliteDialect.placeholder)
+
+	want = `SELECT * FROM T WHERE (("A" < ?1) OR ("A" = ?2 AND "B" > ?3)) ORDER BY "A" DESC, "B" LIMIT ?4 OFFSET ?5`
+	if query != want {
+		t.Errorf("query, want: %s, got: %s", want, query)
+	}
+
+	if want := []any{int64(1), int64(1), "x", int64(5), int64(0)}; !reflect.DeepEqual(args, want) {
+		t.Errorf("args, want: %v, got: %v", want, args)
+	}
+
+	q = Query{Filters: []Filter{AtLeast("A", int64(3))}, Order: []Order{Ascending("A")}}
+
+	query, args = selectStatement("T", q, postgresDialect.quote, postgresDialect.placeholder)
+
+	if want := "SELECT * FROM T WHERE A >= $1 ORDER BY A"; query != want || !reflect.DeepEqual(args, []any{int64(3)}) {
+		t.Errorf("query, want: %s [3], got: %s %v", want, query, args)
+	}
+
+	query, args = selectStatement("T", Query{}, sqliteDialect.quote, sqliteDialect.placeholder)
+
+	if want := "SELECT * FROM T"; query != want || len(args) != 0 {
+		t.Errorf("query, want: %s, got: %s %v", want, query, args)
+	}
+}
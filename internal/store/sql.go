@@ -0,0 +1,498 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sqlRetries is how many times a transaction is attempted before giving up.
+const sqlRetries = 5
+
+// A dialect of SQL spoken by a database. Tables and columns are named as in Spanner.
+type dialect struct {
+	// placeholder for the nth parameter of a statement, counting from one.
+	placeholder func(n int) string
+	// quote an identifier in generated statements.
+	quote func(identifier string) string
+	// array wraps a pointer to, or value of, a string slice so it can be scanned or written.
+	array func(a any) any
+	// now is an expression for the current time, written in place of Spanner's commit timestamp.
+	now string
+	// keyColumns is a query for the primary key columns of the table given as its only parameter, in order.
+	keyColumns string
+	// retryable reports whether a transaction failed only because it conflicted with another.
+	retryable func(error) bool
+}
+
+// A querier runs statements, satisfied by both database connections and transactions.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// An sqlTransaction carries a serializable database transaction.
+type sqlTransaction struct {
+	tx *sql.Tx
+}
+
+// SQLTransactor runs units of work in serializable database transactions.
+type SQLTransactor struct {
+	db      *sql.DB
+	dialect dialect
+}
+
+// InTransaction runs fn in a serializable transaction, committing all writes if it returns nil. The function may be
+// run more than once if the transaction conflicts with another, so it must not have side effects outside of the
+// stores.
+func (s *SQLTransactor) InTransaction(ctx context.Context, fn func(context.Context) error) error {
+	var err error
+
+	for attempt := 0; attempt < sqlRetries; attempt++ {
+		err = s.attempt(ctx, fn)
+		if !s.dialect.retryable(err) {
+			break
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("transaction: %w", s.dialect.sqlError(err))
+	}
+
+	return nil
+}
+
+// attempt fn once in a transaction.
+func (s *SQLTransactor) attempt(ctx context.Context, fn func(context.Context) error) error {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+
+	if err := fn(context.WithValue(ctx, transactionKey{}, &sqlTransaction{tx: tx})); err != nil {
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	return nil
+}
+
+// SQL store for elements, in a database spoken to with database/sql.
+type SQL[T Storable] struct {
+	db         *sql.DB
+	dialect    dialect
+	mu         sync.Mutex
+	primaryKey []string
+}
+
+// querier for the active transaction, or the database if there is none.
+func (s *SQL[T]) querier(ctx context.Context) querier {
+	if txn, ok := ctx.Value(transactionKey{}).(*sqlTransaction); ok {
+		return txn.tx
+	}
+
+	return s.db
+}
+
+// keyColumns of the table, looked up from the database once.
+func (s *SQL[T]) keyColumns(ctx context.Context) ([]string, error) {
+	var item T
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.primaryKey != nil {
+		return s.primaryKey, nil
+	}
+
+	rows, err := s.querier(ctx).QueryContext(ctx, s.dialect.keyColumns, item.Table())
+	if err != nil {
+		return nil, fmt.Errorf("primary key: %w", s.dialect.sqlError(err))
+	}
+
+	defer rows.Close()
+
+	var columns []string
+
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, fmt.Errorf("primary key: %w", err)
+		}
+
+		columns = append(columns, column)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("primary key: %w", s.dialect.sqlError(err))
+	}
+
+	s.primaryKey = columns
+
+	return columns, nil
+}
+
+// Insert an item into the store, replacing any with the same key. Within a transaction the write is visible to
+// later reads in it, and committed with it.
+func (s *SQL[T]) Insert(ctx context.Context, item T) error {
+	key, err := s.keyColumns(ctx)
+	if err != nil {
+		return fmt.Errorf("insert: %w", err)
+	}
+
+	query, args := upsert(s.dialect, item, key)
+
+	if _, err := s.querier(ctx).ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("insert: %w", s.dialect.sqlError(err))
+	}
+
+	return nil
+}
+
+// Get an element from the store by key.
+func (s *SQL[T]) Get(ctx context.Context, key Key) (T, error) {
+	var item T
+
+	columns, err := s.keyColumns(ctx)
+	if err != nil {
+		return item, fmt.Errorf("get: %w", err)
+	}
+
+	conditions := make([]string, len(columns))
+	for i, column := range columns {
+		conditions[i] = fmt.Sprintf("%s = %s", s.dialect.quote(column), s.dialect.placeholder(i+1))
+	}
+
+	query := fmt.Sprintf("SELECT * FROM %s WHERE %s", item.Table(), strings.Join(conditions, " AND "))
+
+	items, err := s.query(ctx, query, key...)
+	if err != nil {
+		return item, fmt.Errorf("get: %w", err)
+	}
+
+	if len(items) == 0 {
+		return item, fmt.Errorf("get: %w", notFound(item.Table(), key))
+	}
+
+	return items[0], nil
+}
+
+// GetMany elements from the store in one query, in the order of their keys. Keys with no element are skipped.
+func (s *SQL[T]) GetMany(ctx context.Context, keys []Key) ([]T, error) {
+	var item T
+
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	columns, err := s.keyColumns(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get many: %w", err)
+	}
+
+	var args []any
+
+	alternatives := make([]string, len(keys))
+
+	for i, key := range keys {
+		conditions := make([]string, len(columns))
+		for j, column := range columns {
+			args = append(args, key[j])
+			conditions[j] = fmt.Sprintf("%s = %s", s.dialect.quote(column), s.dialect.placeholder(len(args)))
+		}
+
+		alternatives[i] = "(" + strings.Join(conditions, " AND ") + ")"
+	}
+
+	query := fmt.Sprintf("SELECT * FROM %s WHERE %s", item.Table(), strings.Join(alternatives, " OR "))
+
+	items, err := s.query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get many: %w", err)
+	}
+
+	found := make(map[string]T, len(items))
+	for _, item := range items {
+		found[item.Key().String()] = item
+	}
+
+	return inKeyOrder(keys, found), nil
+}
+
+// Delete an element from the store by key. Interleaved elements are deleted with it by the schema.
+func (s *SQL[T]) Delete(ctx context.Context, key Key) error {
+	var item T
+
+	columns, err := s.keyColumns(ctx)
+	if err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	conditions := make([]string, len(columns))
+	for i, column := range columns {
+		conditions[i] = fmt.Sprintf("%s = %s", s.dialect.quote(column), s.dialect.placeholder(i+1))
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE %s", item.Table(), strings.Join(conditions, " AND "))
+
+	if _, err := s.querier(ctx).ExecContext(ctx, query, key...); err != nil {
+		return fmt.Errorf("delete: %w", s.dialect.sqlError(err))
+	}
+
+	return nil
+}
+
+// DeleteRange of elements from the store. Interleaved elements are deleted with them by the schema.
+func (s *SQL[T]) DeleteRange(ctx context.Context, r KeyRange) error {
+	var (
+		item       T
+		args       []any
+		conditions []string
+	)
+
+	columns, err := s.keyColumns(ctx)
+	if err != nil {
+		return fmt.Errorf("delete range: %w", err)
+	}
+
+	if len(r.Start) > 0 {
+		conditions = append(conditions, bound(s.dialect, columns, r.Start, ">", &args))
+	}
+
+	if len(r.End) > 0 {
+		conditions = append(conditions, bound(s.dialect, columns, r.End, "<", &args))
+	}
+
+	query := "DELETE FROM " + item.Table()
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	if _, err := s.querier(ctx).ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("delete range: %w", s.dialect.sqlError(err))
+	}
+
+	return nil
+}
+
+// bound condition for keys on the given side of a range bound, or beginning with it. The comparison is > for the
+// start of a range, and < for the end.
+func bound(d dialect, columns []string, key Key, comparison string, args *[]any) string {
+	param := func(value any) string {
+		*args = append(*args, value)
+
+		return d.placeholder(len(*args))
+	}
+
+	alternatives := make([]string, len(key))
+
+	for i := range key {
+		var terms []string
+
+		for j := 0; j < i; j++ {
+			terms = append(terms, fmt.Sprintf("%s = %s", d.quote(columns[j]), param(key[j])))
+		}
+
+		last := comparison
+		if i == len(key)-1 {
+			last += "="
+		}
+
+		terms = append(terms, fmt.Sprintf("%s %s %s", d.quote(columns[i]), last, param(key[i])))
+		alternatives[i] = "(" + strings.Join(terms, " AND ") + ")"
+	}
+
+	return "(" + strings.Join(alternatives, " OR ") + ")"
+}
+
+// List elements with limit and offset for pagination, using the item's List query.
+func (s *SQL[T]) List(ctx context.Context, prefix Key, limit, offset int64) ([]T, error) {
+	var item T
+
+	items, err := s.run(ctx, item.List(prefix, limit, offset))
+	if err != nil {
+		return nil, fmt.Errorf("list: %w", err)
+	}
+
+	return items, nil
+}
+
+// ListMany elements matching the List query for any of the prefixes, in its order.
+func (s *SQL[T]) ListMany(ctx context.Context, prefixes []Key) ([]T, error) {
+	if len(prefixes) == 0 {
+		return nil, nil
+	}
+
+	items, err := s.run(ctx, manyQuery[T](prefixes))
+	if err != nil {
+		return nil, fmt.Errorf("list many: %w", err)
+	}
+
+	return items, nil
+}
+
+// Select elements matching a query, such as one over a secondary index, in its order.
+func (s *SQL[T]) Select(ctx context.Context, q Query) ([]T, error) {
+	items, err := s.run(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("select: %w", err)
+	}
+
+	return items, nil
+}
+
+// Page lists up to limit elements after the one with the given key, in the order of the List query. A nil key
+// starts from the first element.
+func (s *SQL[T]) Page(ctx context.Context, prefix, after Key, limit int64) ([]T, error) {
+	q, err := pageQuery(ctx, s.Get, prefix, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("page: %w", err)
+	}
+
+	items, err := s.run(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("page: %w", err)
+	}
+
+	return items, nil
+}
+
+// run a query in the dialect.
+func (s *SQL[T]) run(ctx context.Context, q Query) ([]T, error) {
+	var item T
+
+	query, args := selectStatement(item.Table(), q, s.dialect.quote, s.dialect.placeholder)
+
+	return s.query(ctx, query, args...)
+}
+
+// query for items, matching result columns to fields by name.
+func (s *SQL[T]) query(ctx context.Context, query string, args ...any) ([]T, error) {
+	rows, err := s.querier(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", s.dialect.sqlError(err))
+	}
+
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	var items []T
+
+	for rows.Next() {
+		var item T
+
+		if err := rows.Scan(scanTargets(s.dialect, &item, columns)...); err != nil {
+			return nil, fmt.Errorf("query: %w", err)
+		}
+
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query: %w", s.dialect.sqlError(err))
+	}
+
+	return items, nil
+}
+
+// scanTargets in item for each column, matched to fields ignoring case. Unknown columns are discarded.
+func scanTargets(d dialect, item any, columns []string) []any {
+	v := reflect.ValueOf(item).Elem()
+	targets := make([]any, len(columns))
+
+	for i, column := range columns {
+		field := v.FieldByNameFunc(func(name string) bool { return strings.EqualFold(name, column) })
+		if !field.IsValid() {
+			targets[i] = new(any)
+
+			continue
+		}
+
+		if _, ok := field.Interface().([]string); ok {
+			targets[i] = d.array(field.Addr().Interface())
+
+			continue
+		}
+
+		targets[i] = field.Addr().Interface()
+	}
+
+	return targets
+}
+
+// upsert statement writing every field of an item, replacing the row with the same key.
+func upsert(d dialect, item Storable, key []string) (string, []any) {
+	v := reflect.ValueOf(item)
+	columns := getColumnsForType(item)
+
+	var (
+		values  []string
+		updates []string
+		args    []any
+	)
+
+	for i, column := range columns {
+		value := v.Field(i).Interface()
+
+		switch value := value.(type) {
+		case time.Time:
+			if value.Equal(CommitTimestamp) {
+				values = append(values, d.now)
+			} else {
+				args = append(args, value)
+				values = append(values, d.placeholder(len(args)))
+			}
+		case []string:
+			args = append(args, d.array(value))
+			values = append(values, d.placeholder(len(args)))
+		default:
+			args = append(args, value)
+			values = append(values, d.placeholder(len(args)))
+		}
+
+		if !containsFold(key, column) {
+			updates = append(updates, fmt.Sprintf("%s = EXCLUDED.%s", d.quote(column), d.quote(column)))
+		}
+
+		columns[i] = d.quote(column)
+	}
+
+	quoted := make([]string, len(key))
+	for i, column := range key {
+		quoted[i] = d.quote(column)
+	}
+
+	query := fmt.Sprintf(
+		"INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (%s) ",
+		item.Table(), strings.Join(columns, ", "), strings.Join(values, ", "), strings.Join(quoted, ", "))
+
+	if len(updates) == 0 {
+		return query + "DO NOTHING", args
+	}
+
+	return query + "DO UPDATE SET " + strings.Join(updates, ", "), args
+}
+
+// containsFold reports whether a column is in a list, ignoring case.
+func containsFold(columns []string, column string) bool {
+	for _, c := range columns {
+		if strings.EqualFold(c, column) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,117 @@
+package store
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// sqliteDialect for SQLite. Arrays are stored as JSON text.
+//
+//nolint:gochecknoglobals // Immutable dialect
+var sqliteDialect = dialect{
+	placeholder: func(n int) string { return fmt.Sprintf("?%d", n) },
+	quote:       func(identifier string) string { return `"` + identifier + `"` },
+	array: func(a any) any {
+		if a, ok := a.(*[]string); ok {
+			return (*jsonArray)(a)
+		}
+
+		//nolint:forcetypeassert // Only string slices are stored as arrays.
+		return jsonArray(a.([]string))
+	},
+	now:        "strftime('%Y-%m-%d %H:%M:%f', 'now')",
+	keyColumns: "SELECT name FROM pragma_table_info(?1) WHERE pk > 0 ORDER BY pk",
+	retryable: func(err error) bool {
+		var sqliteErr *sqlite.Error
+		if !errors.As(err, &sqliteErr) {
+			return false
+		}
+
+		// Extended result codes keep the primary code in the lowest byte.
+		code := sqliteErr.Code() & 0xff
+
+		return code == sqlite3.SQLITE_BUSY || code == sqlite3.SQLITE_LOCKED
+	},
+}
+
+// A jsonArray of strings, stored in SQLite as JSON text.
+type jsonArray []string
+
+// Value of the array as JSON, or NULL if it is nil.
+func (a jsonArray) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	b, err := json.Marshal([]string(a))
+	if err != nil {
+		return nil, fmt.Errorf("array: %w", err)
+	}
+
+	return string(b), nil
+}
+
+// Scan the array from JSON text.
+func (a *jsonArray) Scan(src any) error {
+	switch src := src.(type) {
+	case nil:
+		*a = nil
+
+		return nil
+	case string:
+		return a.unmarshal([]byte(src))
+	case []byte:
+		return a.unmarshal(src)
+	default:
+		return fmt.Errorf("array: %w: %T", errUnsupportedArray, src)
+	}
+}
+
+// unmarshal the array from JSON.
+func (a *jsonArray) unmarshal(b []byte) error {
+	if err := json.Unmarshal(b, (*[]string)(a)); err != nil {
+		return fmt.Errorf("array: %w", err)
+	}
+
+	return nil
+}
+
+// errUnsupportedArray indicates a column could not be scanned as an array.
+var errUnsupportedArray = errors.New("unsupported array type")
+
+// sqliteBusyTimeout is how long to wait for another process to release a lock on the database file.
+const sqliteBusyTimeout = 5 * time.Second
+
+// OpenSQLite database file, creating it if it doesn't exist. The file may be shared with other processes, but
+// connections are limited to one so writes from the same process never contend.
+func OpenSQLite(path string) (*sql.DB, error) {
+	dsn := fmt.Sprintf(
+		"file:%s?_pragma=busy_timeout(%d)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)",
+		path, sqliteBusyTimeout.Milliseconds())
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open: %w", err)
+	}
+
+	db.SetMaxOpenConns(1)
+
+	return db, nil
+}
+
+// TransactInSQLite runs units of work against an SQLite database.
+func TransactInSQLite(db *sql.DB) *SQLTransactor {
+	return &SQLTransactor{db: db, dialect: sqliteDialect}
+}
+
+// InSQLite stores items backed by an SQLite database.
+func InSQLite[T Storable](db *sql.DB) *SQL[T] {
+	return &SQL[T]{db: db, dialect: sqliteDialect}
+}
@@ -0,0 +1,212 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// keyed by a column SQLite reserves as a keyword, so it must be quoted.
+type keyed struct {
+	Address string
+	Index   string
+	Hashes  []string
+}
+
+func (k keyed) Key() Key {
+	return Key{k.Address, k.Index}
+}
+
+func (keyed) Table() string {
+	return "Keyed"
+}
+
+func (keyed) List(prefix Key, limit, offset int64) Query {
+	return Query{
+		Filters: []Filter{Equal("Address", prefix[0])},
+		Order:   []Order{Ascending("Index")},
+		Limit:   limit,
+		Offset:  offset,
+	}
+}
+
+func TestSQLite(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	db, err := OpenSQLite(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.ExecContext(ctx, `CREATE TABLE Keyed `+
+		`(Address TEXT NOT NULL, "Index" TEXT NOT NULL, Hashes TEXT, PRIMARY KEY (Address, "Index"))`); err != nil {
+		t.Fatal(err)
+	}
+
+	items := InSQLite[keyed](db)
+	transactor := TransactInSQLite(db)
+
+	err = transactor.InTransaction(ctx, func(ctx context.Context) error {
+		for _, index := range []string{"c", "a", "b"} {
+			if err := items.Insert(ctx, keyed{Address: "x", Index: index}); err != nil {
+				return err
+			}
+		}
+
+		item, err := items.Get(ctx, Key{"x", "a"})
+		if err != nil {
+			return err
+		}
+
+		item.Hashes = []string{"1", "2"}
+
+		return items.Insert(ctx, item)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := items.Get(ctx, Key{"x", "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got.Hashes, []string{"1", "2"}) {
+		t.Errorf("hashes, want: [1 2], got: %v", got.Hashes)
+	}
+
+	listed, err := items.List(ctx, Key{"x"}, 2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(listed) != 2 || listed[0].Index != "b" || listed[1].Index != "c" || listed[0].Hashes != nil {
+		t.Errorf("list, want: [b c], got: %+v", listed)
+	}
+
+	many, err := items.GetMany(ctx, []Key{{"x", "c"}, {"x", "z"}, {"x", "a"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(many) != 2 || many[0].Index != "c" || many[1].Index != "a" {
+		t.Errorf("get many, want: [c a], got: %+v", many)
+	}
+
+	if err := items.Insert(ctx, keyed{Address: "w", Index: "d"}); err != nil {
+		t.Fatal(err)
+	}
+
+	listedMany, err := items.ListMany(ctx, []Key{{"w"}, {"x"}, {"z"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(listedMany) != 4 || listedMany[0].Index != "a" || listedMany[3].Index != "d" {
+		t.Errorf("list many, want: [a b c d], got: %+v", listedMany)
+	}
+
+	selected, err := items.Select(ctx, Query{
+		Filters: []Filter{AtLeast("Index", "c")},
+		Order:   []Order{Descending("Address")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(selected) != 2 || selected[0].Address != "x" || selected[1].Address != "w" {
+		t.Errorf("select, want: [x/c w/d], got: %+v", selected)
+	}
+
+	paged, err := items.Page(ctx, Key{"x"}, Key{"x", "a"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(paged) != 1 || paged[0].Index != "b" {
+		t.Errorf("page, want: [b], got: %+v", paged)
+	}
+
+	err = transactor.InTransaction(ctx, func(ctx context.Context) error {
+		if err := items.Insert(ctx, keyed{Address: "y", Index: "a"}); err != nil {
+			return err
+		}
+
+		return errRollback
+	})
+	if err == nil {
+		t.Fatal("want error from rolled back transaction")
+	}
+
+	if _, err := items.Get(ctx, Key{"y", "a"}); err == nil {
+		t.Error("write visible after rollback")
+	}
+}
+
+func TestSQLiteDelete(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	db, err := OpenSQLite(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { db.Close() })
+
+	for _, table := range []string{
+		`CREATE TABLE Accounts (Address TEXT PRIMARY KEY, Balance TEXT NOT NULL)`,
+		`CREATE TABLE Keyed (Address TEXT NOT NULL REFERENCES Accounts ON DELETE CASCADE, "Index" TEXT NOT NULL, ` +
+			`Hashes TEXT, PRIMARY KEY (Address, "Index"))`,
+	} {
+		if _, err := db.ExecContext(ctx, table); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	accounts := InSQLite[account](db)
+	items := InSQLite[keyed](db)
+
+	for _, address := range []string{"a", "b"} {
+		if err := accounts.Insert(ctx, account{Address: address, Balance: "0"}); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, index := range []string{"1", "2", "3"} {
+			if err := items.Insert(ctx, keyed{Address: address, Index: index}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if err := items.DeleteRange(ctx, KeyRange{Start: Key{"a", "2"}, End: Key{"b", "1"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := accounts.Delete(ctx, Key{"b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var indexes []string
+
+	for _, address := range []string{"a", "b"} {
+		listed, err := items.List(ctx, Key{address}, 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, item := range listed {
+			indexes = append(indexes, item.Address+item.Index)
+		}
+	}
+
+	if want := []string{"a1"}; !reflect.DeepEqual(indexes, want) {
+		t.Errorf("remaining, want: %v, got: %v", want, indexes)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sync"
+	"time"
 
 	"cloud.google.com/go/spanner"
 	"google.golang.org/api/iterator"
@@ -14,15 +16,27 @@ import (
 // Store elements of type T in a database.
 type Store[T Storable] interface {
 	Insert(context.Context, T) error
-	Get(context.Context, spanner.Key) (T, error)
-	List(context.Context, spanner.Key, int64, int64) ([]T, error)
+	Get(context.Context, Key) (T, error)
+	GetMany(context.Context, []Key) ([]T, error)
+	List(context.Context, Key, int64, int64) ([]T, error)
+	ListMany(ctx context.Context, prefixes []Key) ([]T, error)
+	Select(context.Context, Query) ([]T, error)
+	Page(ctx context.Context, prefix, after Key, limit int64) ([]T, error)
+	Delete(context.Context, Key) error
+	DeleteRange(context.Context, KeyRange) error
 }
 
-// Storable in a database.
+// Storable in a database. List describes the items listed under a key prefix, which each backend translates.
 type Storable interface {
-	Key() spanner.Key
+	Key() Key
 	Table() string
-	List(prefix spanner.Key, limit, offset int64) spanner.Statement
+	List(prefix Key, limit, offset int64) Query
+}
+
+// A Transactor runs units of work atomically. Any Store used with the context passed to the unit of work will
+// read and write as part of the same transaction, and all writes are committed together or not at all.
+type Transactor interface {
+	InTransaction(context.Context, func(context.Context) error) error
 }
 
 // getColumnsForType helps to simplify the Spanner logic for what columns to retrieve.
@@ -39,17 +53,231 @@ func getColumnsForType(x any) []string {
 	return columns
 }
 
+// transactionKey to find an active transaction in a context.
+type transactionKey struct{}
+
+// pending writes buffered in a transaction. Reads in the same transaction don't return buffered writes from the
+// database, so they are tracked here to allow read-modify-write of the same row within a unit of work.
+type pending struct {
+	sync.Mutex
+	items   map[string]map[string]Storable
+	deleted map[string][]KeyRange
+}
+
+// put an item written in the transaction.
+func (p *pending) put(item Storable) {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.items == nil {
+		p.items = make(map[string]map[string]Storable)
+	}
+
+	table, ok := p.items[item.Table()]
+	if !ok {
+		table = make(map[string]Storable)
+		p.items[item.Table()] = table
+	}
+
+	table[item.Key().String()] = item
+}
+
+// remove items in a range deleted in the transaction, including any written earlier in it.
+func (p *pending) remove(table string, r KeyRange) {
+	p.Lock()
+	defer p.Unlock()
+
+	for key, item := range p.items[table] {
+		if r.contains(item.Key()) {
+			delete(p.items[table], key)
+		}
+	}
+
+	if p.deleted == nil {
+		p.deleted = make(map[string][]KeyRange)
+	}
+
+	p.deleted[table] = append(p.deleted[table], r)
+}
+
+// get an item previously written in this transaction. If it was deleted since, it is found but nil.
+func (p *pending) get(table string, key Key) (Storable, bool) {
+	p.Lock()
+	defer p.Unlock()
+
+	if item, ok := p.items[table][key.String()]; ok {
+		return item, true
+	}
+
+	for _, r := range p.deleted[table] {
+		if r.contains(key) {
+			return nil, true
+		}
+	}
+
+	return nil, false
+}
+
+// A spannerTransaction carries a read-write transaction and the writes buffered in it.
+type spannerTransaction struct {
+	pending
+	rw *spanner.ReadWriteTransaction
+}
+
+// buffer a write to the transaction.
+func (t *spannerTransaction) buffer(item Storable, mutation *spanner.Mutation) error {
+	if err := t.rw.BufferWrite([]*spanner.Mutation{mutation}); err != nil {
+		return fmt.Errorf("buffer: %w", spannerError(err))
+	}
+
+	t.put(item)
+
+	return nil
+}
+
+// bufferDelete of a range from a table in the transaction.
+func (t *spannerTransaction) bufferDelete(table string, r KeyRange, mutation *spanner.Mutation) error {
+	if err := t.rw.BufferWrite([]*spanner.Mutation{mutation}); err != nil {
+		return fmt.Errorf("buffer: %w", spannerError(err))
+	}
+
+	t.remove(table, r)
+
+	return nil
+}
+
+// transactionFrom a context, if one is active.
+func transactionFrom(ctx context.Context) (*spannerTransaction, bool) {
+	txn, ok := ctx.Value(transactionKey{}).(*spannerTransaction)
+
+	return txn, ok
+}
+
+// A reader of rows, satisfied by both single use and read-write Spanner transactions.
+type reader interface {
+	ReadRow(ctx context.Context, table string, key spanner.Key, columns []string) (*spanner.Row, error)
+	Read(ctx context.Context, table string, keys spanner.KeySet, columns []string) *spanner.RowIterator
+	Query(ctx context.Context, statement spanner.Statement) *spanner.RowIterator
+}
+
+// SpannerTransactor runs units of work in Spanner read-write transactions.
+type SpannerTransactor struct {
+	client *spanner.Client
+}
+
+// TransactInSpanner runs units of work against a Spanner database.
+func TransactInSpanner(client *spanner.Client) *SpannerTransactor {
+	return &SpannerTransactor{client: client}
+}
+
+// InTransaction runs fn in a read-write transaction, committing all writes if it returns nil. The function may be
+// run more than once if Spanner aborts the transaction, so it must not have side effects outside of the stores.
+func (s *SpannerTransactor) InTransaction(ctx context.Context, fn func(context.Context) error) error {
+	_, err := s.client.ReadWriteTransaction(ctx, func(ctx context.Context, rw *spanner.ReadWriteTransaction) error {
+		txn := &spannerTransaction{rw: rw}
+
+		return fn(context.WithValue(ctx, transactionKey{}, txn))
+	})
+	if err != nil {
+		return fmt.Errorf("transaction: %w", spannerError(err))
+	}
+
+	return nil
+}
+
+// readTimestampKey to find where the timestamps of reads are recorded in a context.
+type readTimestampKey struct{}
+
+// A readTimestamp of the oldest data read with a context.
+type readTimestamp struct {
+	sync.Mutex
+	oldest time.Time
+}
+
+// RecordReadTimestamp of reads made with the returned context, to be found with ReadTimestamp. Only Spanner reads
+// outside of transactions record their timestamp, as other reads are always of the latest data.
+func RecordReadTimestamp(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readTimestampKey{}, &readTimestamp{})
+}
+
+// ReadTimestamp of the oldest data read with a context from RecordReadTimestamp, if any read recorded one.
+func ReadTimestamp(ctx context.Context) (time.Time, bool) {
+	recorded, ok := ctx.Value(readTimestampKey{}).(*readTimestamp)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	recorded.Lock()
+	defer recorded.Unlock()
+
+	return recorded.oldest, !recorded.oldest.IsZero()
+}
+
+// recordRead of data at a timestamp, if the context is recording them.
+func recordRead(ctx context.Context, t time.Time) {
+	recorded, ok := ctx.Value(readTimestampKey{}).(*readTimestamp)
+	if !ok {
+		return
+	}
+
+	recorded.Lock()
+	defer recorded.Unlock()
+
+	if recorded.oldest.IsZero() || t.Before(recorded.oldest) {
+		recorded.oldest = t
+	}
+}
+
+// A SpannerOption configures a Spanner store.
+type SpannerOption func(*spannerOptions)
+
+// spannerOptions shared by the Spanner stores of each type.
+type spannerOptions struct {
+	bound spanner.TimestampBound
+}
+
+// WithTimestampBound reads outside of transactions at the given bound, such as spanner.MaxStaleness, instead of
+// strongly. Stale reads are cheaper and faster, at the cost of missing the latest writes.
+func WithTimestampBound(bound spanner.TimestampBound) SpannerOption {
+	return func(o *spannerOptions) {
+		o.bound = bound
+	}
+}
+
 // Spanner store for elements.
 type Spanner[T Storable] struct {
 	client *spanner.Client
+	bound  spanner.TimestampBound
 }
 
 // InSpanner stores items backed by a Spanner database.
-func InSpanner[T Storable](client *spanner.Client) *Spanner[T] {
-	return &Spanner[T]{client: client}
+func InSpanner[T Storable](client *spanner.Client, opts ...SpannerOption) *Spanner[T] {
+	o := spannerOptions{bound: spanner.StrongRead()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &Spanner[T]{client: client, bound: o.bound}
 }
 
-// Insert an item into the store.
+// reader for the active transaction, or a single use read at the store's timestamp bound if there is none. The
+// returned function records the timestamp of a single use read once it is done.
+func (s *Spanner[T]) reader(ctx context.Context) (reader, func()) {
+	if txn, ok := transactionFrom(ctx); ok {
+		return txn.rw, func() {}
+	}
+
+	ro := s.client.Single().WithTimestampBound(s.bound)
+
+	return ro, func() {
+		// Reads that failed before reaching Spanner have no timestamp.
+		if t, err := ro.Timestamp(); err == nil {
+			recordRead(ctx, t)
+		}
+	}
+}
+
+// Insert an item into the store. Within a transaction the write is buffered until it commits.
 func (s *Spanner[T]) Insert(ctx context.Context, item T) error {
 	var mutations []*spanner.Mutation
 
@@ -58,22 +286,53 @@ func (s *Spanner[T]) Insert(ctx context.Context, item T) error {
 		return fmt.Errorf("insert: %w", err)
 	}
 
+	if txn, ok := transactionFrom(ctx); ok {
+		if err := txn.buffer(item, m); err != nil {
+			return fmt.Errorf("insert: %w", err)
+		}
+
+		return nil
+	}
+
 	mutations = append(mutations, m)
 
 	if _, err := s.client.Apply(ctx, mutations); err != nil {
-		return fmt.Errorf("insert: %w", err)
+		return fmt.Errorf("insert: %w", spannerError(err))
 	}
 
 	return nil
 }
 
-// Get an element from the store by key.
-func (s *Spanner[T]) Get(ctx context.Context, key spanner.Key) (T, error) {
+// Get an element from the store by key. Within a transaction, items written earlier in it are returned.
+func (s *Spanner[T]) Get(ctx context.Context, key Key) (T, error) {
 	var item T
 
-	row, err := s.client.Single().ReadRow(ctx, item.Table(), key, getColumnsForType(item))
+	if txn, ok := transactionFrom(ctx); ok {
+		if pending, ok := txn.get(item.Table(), key); ok {
+			if pending == nil {
+				return item, fmt.Errorf("get: %w", notFound(item.Table(), key))
+			}
+
+			//nolint:forcetypeassert // Items are only ever buffered under their own table.
+			return pending.(T), nil
+		}
+	}
+
+	r, done := s.reader(ctx)
+
+	// Read rather than ReadRow, so a missing row isn't confused with the NotFound of a missing table or database.
+	iter := r.Read(ctx, item.Table(), spanner.Key(key), getColumnsForType(item))
+	defer iter.Stop()
+
+	row, err := iter.Next()
+	done()
+
+	if errors.Is(err, iterator.Done) {
+		return item, fmt.Errorf("get: %w", notFound(item.Table(), key))
+	}
+
 	if err != nil {
-		return item, fmt.Errorf("get: %w", err)
+		return item, fmt.Errorf("get: %w", spannerError(err))
 	}
 
 	if err := row.ToStruct(&item); err != nil {
@@ -83,13 +342,156 @@ func (s *Spanner[T]) Get(ctx context.Context, key spanner.Key) (T, error) {
 	return item, nil
 }
 
-// List elements with limit and offset for pagination.
-func (s *Spanner[T]) List(ctx context.Context, prefix spanner.Key, limit, offset int64) ([]T, error) {
+// Delete an element from the store by key, along with any interleaved in it. Within a transaction the delete is
+// buffered until it commits. Interleaved elements deleted with it may still be read until then.
+func (s *Spanner[T]) Delete(ctx context.Context, key Key) error {
+	if err := s.delete(ctx, Prefix(key), spanner.Key(key)); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteRange of elements from the store, along with any interleaved in them. Within a transaction the delete is
+// buffered until it commits. Interleaved elements deleted with them may still be read until then.
+func (s *Spanner[T]) DeleteRange(ctx context.Context, r KeyRange) error {
+	keys := spanner.KeyRange{Start: spanner.Key(r.Start), End: spanner.Key(r.End), Kind: spanner.ClosedClosed}
+
+	if err := s.delete(ctx, r, keys); err != nil {
+		return fmt.Errorf("delete range: %w", err)
+	}
+
+	return nil
+}
+
+// delete the keys in a range, given as a key set for Spanner.
+func (s *Spanner[T]) delete(ctx context.Context, r KeyRange, keys spanner.KeySet) error {
+	var item T
+
+	mutation := spanner.Delete(item.Table(), keys)
+
+	if txn, ok := transactionFrom(ctx); ok {
+		return txn.bufferDelete(item.Table(), r, mutation)
+	}
+
+	if _, err := s.client.Apply(ctx, []*spanner.Mutation{mutation}); err != nil {
+		return fmt.Errorf("apply: %w", spannerError(err))
+	}
+
+	return nil
+}
+
+// GetMany elements from the store in one read, in the order of their keys. Keys with no element are skipped. Within a
+// transaction, items written earlier in it are returned.
+func (s *Spanner[T]) GetMany(ctx context.Context, keys []Key) ([]T, error) {
+	var item T
+
+	found := make(map[string]T, len(keys))
+	remaining := make([]spanner.Key, 0, len(keys))
+
+	txn, inTransaction := transactionFrom(ctx)
+
+	for _, key := range keys {
+		if inTransaction {
+			if pending, ok := txn.get(item.Table(), key); ok {
+				if pending != nil {
+					//nolint:forcetypeassert // Items are only ever buffered under their own table.
+					found[key.String()] = pending.(T)
+				}
+
+				continue
+			}
+		}
+
+		remaining = append(remaining, spanner.Key(key))
+	}
+
+	if len(remaining) > 0 {
+		r, done := s.reader(ctx)
+		iter := r.Read(ctx, item.Table(), spanner.KeySetFromKeys(remaining...), getColumnsForType(item))
+
+		defer done()
+
+		err := iter.Do(func(row *spanner.Row) error {
+			var item T
+			if err := row.ToStruct(&item); err != nil {
+				return err
+			}
+
+			found[item.Key().String()] = item
+
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("get many: %w", spannerError(err))
+		}
+	}
+
+	return inKeyOrder(keys, found), nil
+}
+
+// List elements with limit and offset for pagination. Writes buffered in a transaction are not included.
+func (s *Spanner[T]) List(ctx context.Context, prefix Key, limit, offset int64) ([]T, error) {
+	var item T
+
+	items, err := s.query(ctx, item.List(prefix, limit, offset))
+	if err != nil {
+		return nil, fmt.Errorf("list: %w", err)
+	}
+
+	return items, nil
+}
+
+// ListMany elements matching the List query for any of the prefixes, in its order.
+func (s *Spanner[T]) ListMany(ctx context.Context, prefixes []Key) ([]T, error) {
+	if len(prefixes) == 0 {
+		return nil, nil
+	}
+
+	items, err := s.query(ctx, manyQuery[T](prefixes))
+	if err != nil {
+		return nil, fmt.Errorf("list many: %w", err)
+	}
+
+	return items, nil
+}
+
+// Select elements matching a query, such as one over a secondary index, in its order.
+func (s *Spanner[T]) Select(ctx context.Context, q Query) ([]T, error) {
+	items, err := s.query(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("select: %w", err)
+	}
+
+	return items, nil
+}
+
+// Page lists up to limit elements after the one with the given key, in the order of the List query. A nil key
+// starts from the first element.
+func (s *Spanner[T]) Page(ctx context.Context, prefix, after Key, limit int64) ([]T, error) {
+	q, err := pageQuery(ctx, s.Get, prefix, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("page: %w", err)
+	}
+
+	items, err := s.query(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("page: %w", err)
+	}
+
+	return items, nil
+}
+
+// query for the elements matching a query.
+func (s *Spanner[T]) query(ctx context.Context, q Query) ([]T, error) {
 	var item T
 
 	var items []T
 
-	iter := s.client.Single().Query(ctx, item.List(prefix, limit, offset))
+	r, done := s.reader(ctx)
+	iter := r.Query(ctx, spannerStatement(item.Table(), q))
+
+	defer done()
 
 	for {
 		row, err := iter.Next()
@@ -98,11 +500,11 @@ func (s *Spanner[T]) List(ctx context.Context, prefix spanner.Key, limit, offset
 		}
 
 		if err != nil {
-			return nil, fmt.Errorf("list: %w", err)
+			return nil, fmt.Errorf("query: %w", spannerError(err))
 		}
 
 		if err := row.ToStruct(&item); err != nil {
-			return nil, fmt.Errorf("list: %w", err)
+			return nil, fmt.Errorf("query: %w", err)
 		}
 
 		items = append(items, item)
@@ -110,3 +512,17 @@ func (s *Spanner[T]) List(ctx context.Context, prefix spanner.Key, limit, offset
 
 	return items, nil
 }
+
+// spannerStatement for a query, with named parameters.
+func spannerStatement(table string, q Query) spanner.Statement {
+	query, args := selectStatement(table, q,
+		func(column string) string { return column },
+		func(n int) string { return fmt.Sprintf("@p%d", n) })
+
+	stmt := spanner.NewStatement(query)
+	for i, arg := range args {
+		stmt.Params[fmt.Sprintf("p%d", i+1)] = arg
+	}
+
+	return stmt
+}
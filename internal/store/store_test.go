@@ -1 +1,297 @@
 package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	_ "github.com/golang-migrate/migrate/v4/database/spanner"
+
+	"github.com/alicenet/utilities/internal/migrations"
+)
+
+var errRollback = errors.New("rollback")
+
+// account mirrors the Accounts table for exercising the Spanner store.
+type account struct {
+	Address string
+	Balance string
+}
+
+func (a account) Key() Key {
+	return Key{a.Address}
+}
+
+func (account) Table() string {
+	return "Accounts"
+}
+
+func (account) List(_ Key, limit, offset int64) Query {
+	return Query{Order: []Order{Ascending("Address")}, Limit: limit, Offset: offset}
+}
+
+// accountTransaction mirrors the AccountTransactions table, listed under an address.
+type accountTransaction struct {
+	Address         string
+	TransactionHash string
+	ObserveTime     time.Time
+}
+
+func (a accountTransaction) Key() Key {
+	return Key{a.Address, a.TransactionHash}
+}
+
+func (accountTransaction) Table() string {
+	return "AccountTransactions"
+}
+
+func (accountTransaction) List(prefix Key, limit, offset int64) Query {
+	return Query{
+		Filters: []Filter{Equal("Address", prefix[0])},
+		Order:   []Order{Descending("TransactionHash")},
+		Limit:   limit,
+		Offset:  offset,
+	}
+}
+
+// unmigrated has no table, as when a migration hasn't been run.
+type unmigrated struct {
+	Address string
+}
+
+func (u unmigrated) Key() Key {
+	return Key{u.Address}
+}
+
+func (unmigrated) Table() string {
+	return "Unmigrated"
+}
+
+func (unmigrated) List(_ Key, limit, offset int64) Query {
+	return Query{Order: []Order{Ascending("Address")}, Limit: limit, Offset: offset}
+}
+
+//nolint:paralleltest // t.Parallel not supported with t.Setenv
+func TestInTransaction(t *testing.T) {
+	migrations.SetupEmulator(t)
+	migrations.RunTestMigrations(t)
+
+	client := migrations.EmulatorClient(t)
+	accounts := InSpanner[account](client)
+	transactor := TransactInSpanner(client)
+	ctx := context.Background()
+
+	err := transactor.InTransaction(ctx, func(ctx context.Context) error {
+		if err := accounts.Insert(ctx, account{Address: "a", Balance: "1"}); err != nil {
+			return err
+		}
+
+		got, err := accounts.Get(ctx, Key{"a"})
+		if err != nil {
+			return err
+		}
+
+		if got.Balance != "1" {
+			t.Errorf("read within transaction, want: %s, got: %s", "1", got.Balance)
+		}
+
+		got.Balance = "2"
+
+		return accounts.Insert(ctx, got)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := accounts.Get(ctx, Key{"a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Balance != "2" {
+		t.Errorf("read after commit, want: %s, got: %s", "2", got.Balance)
+	}
+
+	err = transactor.InTransaction(ctx, func(ctx context.Context) error {
+		if err := accounts.Insert(ctx, account{Address: "b", Balance: "1"}); err != nil {
+			return err
+		}
+
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("want: %v, got: %v", errRollback, err)
+	}
+
+	if _, err := accounts.Get(ctx, Key{"b"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("rolled back write should not be found, got: %v", err)
+	}
+}
+
+//nolint:paralleltest // t.Parallel not supported with t.Setenv
+func TestSpannerRead(t *testing.T) {
+	migrations.SetupEmulator(t)
+	migrations.RunTestMigrations(t)
+
+	accounts := InSpanner[account](migrations.EmulatorClient(t))
+	ctx := context.Background()
+
+	for _, address := range []string{"c", "a", "b"} {
+		if err := accounts.Insert(ctx, account{Address: address, Balance: "0"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := accounts.List(ctx, nil, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 2 || got[0].Address != "b" || got[1].Address != "c" {
+		t.Errorf("offset without limit, want: [b c], got: %v", got)
+	}
+
+	got, err = accounts.Page(ctx, nil, Key{"b"}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 1 || got[0].Address != "c" {
+		t.Errorf("page, want: [c], got: %v", got)
+	}
+
+	got, err = accounts.GetMany(ctx, []Key{{"c"}, {"x"}, {"a"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 2 || got[0].Address != "c" || got[1].Address != "a" {
+		t.Errorf("get many, want: [c a], got: %v", got)
+	}
+
+	if _, err := accounts.Get(ctx, Key{"x"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing row, want: %v, got: %v", ErrNotFound, err)
+	}
+
+	transactions := InSpanner[accountTransaction](migrations.EmulatorClient(t))
+
+	for _, address := range []string{"a", "b", "c"} {
+		txn := accountTransaction{Address: address, TransactionHash: address + "1", ObserveTime: CommitTimestamp}
+		if err := transactions.Insert(ctx, txn); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	listed, err := transactions.ListMany(ctx, []Key{{"a"}, {"c"}, {"x"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(listed) != 2 || listed[0].TransactionHash != "c1" || listed[1].TransactionHash != "a1" {
+		t.Errorf("list many, want: [c1 a1], got: %v", listed)
+	}
+
+	listed, err = transactions.Select(ctx, Query{
+		Filters: []Filter{AtLeast("TransactionHash", "b1")},
+		Order:   []Order{Ascending("Address")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(listed) != 2 || listed[0].TransactionHash != "b1" || listed[1].TransactionHash != "c1" {
+		t.Errorf("select, want: [b1 c1], got: %v", listed)
+	}
+
+	// A missing table is an error, not an empty one.
+	if _, err := InSpanner[unmigrated](migrations.EmulatorClient(t)).Get(ctx, Key{"a"}); err == nil ||
+		errors.Is(err, ErrNotFound) {
+		t.Errorf("missing table, want: unclassified error, got: %v", err)
+	}
+}
+
+//nolint:paralleltest // t.Parallel not supported with t.Setenv
+func TestSpannerDelete(t *testing.T) {
+	migrations.SetupEmulator(t)
+	migrations.RunTestMigrations(t)
+
+	accounts := InSpanner[account](migrations.EmulatorClient(t))
+	ctx := context.Background()
+
+	for _, address := range []string{"a", "b", "c"} {
+		if err := accounts.Insert(ctx, account{Address: address, Balance: "0"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The emulator doesn't cascade deletes to interleaved tables, so only the parent is checked.
+	if err := accounts.Delete(ctx, Key{"a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := accounts.DeleteRange(ctx, KeyRange{Start: Key{"c"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := accounts.List(ctx, nil, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 1 || got[0].Address != "b" {
+		t.Errorf("remaining, want: [b], got: %v", got)
+	}
+}
+
+//nolint:paralleltest // t.Parallel not supported with t.Setenv
+func TestSpannerStaleRead(t *testing.T) {
+	migrations.SetupEmulator(t)
+	migrations.RunTestMigrations(t)
+
+	client := migrations.EmulatorClient(t)
+	accounts := InSpanner[account](client, WithTimestampBound(spanner.MaxStaleness(time.Minute)))
+	ctx := context.Background()
+
+	if err := accounts.Insert(ctx, account{Address: "a", Balance: "0"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The emulator always reads the latest data and doesn't report the timestamp, so only reads succeeding at a
+	// bound can be checked here.
+	if _, err := accounts.Get(RecordReadTimestamp(ctx), Key{"a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := accounts.List(RecordReadTimestamp(ctx), nil, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadTimestamp(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Now()
+
+	recordRead(ctx, now)
+
+	if _, ok := ReadTimestamp(ctx); ok {
+		t.Error("read timestamp found without recording")
+	}
+
+	recording := RecordReadTimestamp(ctx)
+
+	if _, ok := ReadTimestamp(recording); ok {
+		t.Error("read timestamp found before any reads")
+	}
+
+	for _, read := range []time.Time{now, now.Add(-time.Second), now.Add(time.Second)} {
+		recordRead(recording, read)
+	}
+
+	if got, _ := ReadTimestamp(recording); !got.Equal(now.Add(-time.Second)) {
+		t.Errorf("oldest read, want: %v, got: %v", now.Add(-time.Second), got)
+	}
+}